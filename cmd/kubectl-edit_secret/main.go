@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/BardiaYaghmaie/kubectl-edit-secret/pkg/cmd"
@@ -8,6 +9,9 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
+// version is set at build time via -ldflags.
+var version = "dev"
+
 func main() {
 	flags := pflag.NewFlagSet("kubectl-edit-secret", pflag.ExitOnError)
 	pflag.CommandLine = flags
@@ -18,8 +22,14 @@ func main() {
 		ErrOut: os.Stderr,
 	}
 
+	cmd.Version = version
+
 	rootCmd := cmd.NewEditSecretCmd(streams)
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
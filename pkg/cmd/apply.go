@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+)
+
+// fieldManager is the field manager recorded for server-side applies
+const fieldManager = "kubectl-edit-secret"
+
+// applyServerSide writes the change set with a server-side apply holding the
+// added and changed keys, so fields owned by other managers are left alone.
+// Keys this plugin applied before are sent again with their current values,
+// since the server deletes applied fields that an apply leaves out. Keys
+// owned by another field manager are a conflict unless --force-conflicts is
+// set. secret must already have the change set written into it.
+func (o *EditSecretOptions) applyServerSide(ctx context.Context, secret *corev1.Secret, cs changeSet) (*corev1.Secret, error) {
+	if len(cs.Removed) > 0 {
+		return nil, fmt.Errorf("--server-side cannot remove keys (%s); no changes were applied. Run without --server-side to remove them", strings.Join(cs.Removed, ", "))
+	}
+
+	owned, err := appliedFields(secret, "f:data")
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string][]byte, len(owned)+len(cs.Added)+len(cs.Changed))
+	for _, k := range owned {
+		if v, ok := secret.Data[k]; ok {
+			data[k] = v
+		}
+	}
+	for _, k := range cs.Added {
+		data[k] = secret.Data[k]
+	}
+	for _, k := range cs.Changed {
+		data[k] = secret.Data[k]
+	}
+	config := corev1ac.Secret(o.secretName, o.namespace).WithData(data)
+
+	ownedAnnotations, err := appliedFields(secret, "f:metadata", "f:annotations")
+	if err != nil {
+		return nil, err
+	}
+	annotations := make(map[string]string)
+	for _, k := range ownedAnnotations {
+		if v, ok := secret.Annotations[k]; ok {
+			annotations[k] = v
+		}
+	}
+	if o.snapshot {
+		annotations[snapshotAnnotation] = secret.Annotations[snapshotAnnotation]
+	}
+	if o.record {
+		annotations[corev1.LastAppliedConfigAnnotation] = secret.Annotations[corev1.LastAppliedConfigAnnotation]
+	}
+	if len(annotations) > 0 {
+		config.WithAnnotations(annotations)
+	}
+	if o.expectRV != "" {
+		config.WithResourceVersion(o.expectRV)
+	}
+
+	opts := metav1.ApplyOptions{FieldManager: fieldManager, Force: o.forceConflicts}
+	if o.dryRun == dryRunServer {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	applied, err := o.clientset.CoreV1().Secrets(o.namespace).Apply(ctx, config, opts)
+	if apierrors.IsConflict(err) {
+		if o.expectRV != "" {
+			return nil, o.resourceVersionMismatch("")
+		}
+		return nil, fmt.Errorf("failed to apply secret: %w; no changes were applied. Pass --force-conflicts to take over the keys", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply secret: %w", o.forbiddenError("patch", err))
+	}
+	return applied, nil
+}
+
+// appliedFields returns the names of the fields under path, such as the data
+// keys under "f:data", that fieldManager owns through earlier applies
+func appliedFields(secret *corev1.Secret, path ...string) ([]string, error) {
+	var names []string
+	for _, entry := range secret.ManagedFields {
+		if entry.Manager != fieldManager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+
+		var fields map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			return nil, fmt.Errorf("failed to parse managed fields of secret %s: %w", secret.Name, err)
+		}
+		for _, p := range path {
+			fields, _ = fields[p].(map[string]interface{})
+		}
+		for f := range fields {
+			if name, ok := strings.CutPrefix(f, "f:"); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
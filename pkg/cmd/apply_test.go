@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRunServerSideSendsOnlyChangedKeys(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "user": "admin"}))
+	o.serverSide = true
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+	var patch k8stesting.PatchAction
+	clientset.PrependReactor("patch", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch = action.(k8stesting.PatchAction)
+		return true, testSecret(map[string]string{"password": "new", "user": "admin"}), nil
+	})
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if patch == nil {
+		t.Fatal("no patch action")
+	}
+	if patch.GetPatchType() != types.ApplyPatchType {
+		t.Errorf("patch type = %s, want %s", patch.GetPatchType(), types.ApplyPatchType)
+	}
+
+	var body struct {
+		Data map[string][]byte `json:"data"`
+	}
+	if err := json.Unmarshal(patch.GetPatch(), &body); err != nil {
+		t.Fatalf("decoding apply body: %v", err)
+	}
+	if len(body.Data) != 1 || string(body.Data["password"]) != "new" {
+		t.Errorf("apply data = %q, want only password=new", body.Data)
+	}
+}
+
+func TestRunServerSideReportsConflict(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	o.serverSide = true
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+	clientset.PrependReactor("patch", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewConflict(corev1.Resource("secrets"), "db", nil)
+	})
+
+	err := o.Run()
+	if err == nil || !strings.Contains(err.Error(), "--force-conflicts") {
+		t.Fatalf("Run() error = %v, want a conflict pointing at --force-conflicts", err)
+	}
+}
+
+// applyReactor stands in for server-side apply, which the fake clientset does
+// not implement: it merges the applied data into the stored secret and
+// records fieldManager as owning the applied keys. It returns the apply
+// bodies it received.
+func applyReactor(t *testing.T, clientset *fake.Clientset) *[]map[string][]byte {
+	t.Helper()
+
+	var bodies []map[string][]byte
+	clientset.PrependReactor("patch", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		var body struct {
+			Data map[string][]byte `json:"data"`
+		}
+		if err := json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &body); err != nil {
+			t.Fatalf("decoding apply body: %v", err)
+		}
+		bodies = append(bodies, body.Data)
+
+		gvr := corev1.SchemeGroupVersion.WithResource("secrets")
+		obj, err := clientset.Tracker().Get(gvr, "default", "db")
+		if err != nil {
+			t.Fatal(err)
+		}
+		secret := obj.(*corev1.Secret).DeepCopy()
+		owned := map[string]interface{}{}
+		for k, v := range body.Data {
+			secret.Data[k] = v
+			owned["f:"+k] = map[string]interface{}{}
+		}
+		raw, _ := json.Marshal(map[string]interface{}{"f:data": owned})
+		secret.ManagedFields = []metav1.ManagedFieldsEntry{{
+			Manager:    fieldManager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: raw},
+		}}
+		if err := clientset.Tracker().Update(gvr, secret, "default"); err != nil {
+			t.Fatal(err)
+		}
+		return true, secret, nil
+	})
+	return &bodies
+}
+
+func TestRunServerSideKeepsPreviouslyAppliedKeys(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "user": "admin"}))
+	bodies := applyReactor(t, clientset)
+
+	// The first run adds token, the second changes password
+	o.serverSide = true
+	fakeEditor(o, 0, func(_ int, content string) string { return content + "token: t1\n" })
+	if err := o.Run(); err != nil {
+		t.Fatalf("first Run() error = %v", err)
+	}
+
+	o, _, _, _ = newTestOptions(t)
+	o.clientset = clientset
+	o.serverSide = true
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+	if err := o.Run(); err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+
+	if len(*bodies) != 2 {
+		t.Fatalf("got %d applies, want 2", len(*bodies))
+	}
+	second := (*bodies)[1]
+	if string(second["token"]) != "t1" || string(second["password"]) != "new" {
+		t.Errorf("second apply data = %q, want token kept alongside password", second)
+	}
+	if _, ok := second["user"]; ok {
+		t.Errorf("second apply data = %q, want user left to its manager", second)
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// writeBackup saves the secret as it was before editing to the --backup path,
+// as a manifest that can be restored with kubectl apply -f. If the path is a
+// directory, the file is named namespace-name-timestamp.yaml in it. Existing
+// files are never overwritten.
+func (o *EditSecretOptions) writeBackup(secret *corev1.Secret) error {
+	path := o.backupPath
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		name := fmt.Sprintf("%s-%s-%s.yaml", secret.Namespace, secret.Name, time.Now().UTC().Format("20060102T150405Z"))
+		path = filepath.Join(path, name)
+	}
+
+	// Server-populated fields would make the manifest fail or conflict on apply
+	backup := secret.DeepCopy()
+	backup.ResourceVersion = ""
+	backup.UID = ""
+	backup.CreationTimestamp = metav1.Time{}
+	backup.ManagedFields = nil
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write backup, no changes were applied: %w", err)
+	}
+	if err := printSecret(f, backup, outputYAML); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write backup, no changes were applied: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write backup, no changes were applied: %w", err)
+	}
+
+	fmt.Fprintf(o.streams.ErrOut, "Backup of secret %s written to %s\n", o.secretName, path)
+	return nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestApplyChangesBackup(t *testing.T) {
+	tests := []struct {
+		dryRun  string
+		backups int
+	}{
+		{dryRunNone, 1},
+		{dryRunClient, 0},
+		{dryRunServer, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dryRun, func(t *testing.T) {
+			live := testSecret(map[string]string{"password": "old"})
+			o, _, _, _ := newTestOptions(t, live)
+			o.backupPath = t.TempDir()
+			o.dryRun = tt.dryRun
+
+			original := map[string]string{"password": "old"}
+			edited := map[string]string{"password": "new"}
+			if _, err := o.applyChanges(context.Background(), live.DeepCopy(), original, edited); err != nil {
+				t.Fatalf("applyChanges() error = %v", err)
+			}
+
+			entries, err := os.ReadDir(o.backupPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.backups {
+				t.Errorf("backup directory has %d files, want %d", len(entries), tt.backups)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"sort"
+)
+
+// isStoredBase64 reports whether v is base64 of printable text in its
+// canonical form, so decoding and re-encoding it gives back v exactly. Values
+// that merely use the base64 alphabet, such as "password", decode to binary
+// and do not count.
+func isStoredBase64(v string) bool {
+	if !looksBase64Encoded(v) {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return false
+	}
+	return base64.StdEncoding.EncodeToString(decoded) == v
+}
+
+// decodeBase64Values replaces the values that are themselves base64-encoded
+// with their decoded text for --decode-base64-values, and returns the sorted
+// keys it decoded
+func decodeBase64Values(data map[string]string) []string {
+	var keys []string
+	for k, v := range data {
+		if !isStoredBase64(v) {
+			continue
+		}
+		decoded, _ := base64.StdEncoding.DecodeString(v)
+		data[k] = string(decoded)
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// encodeBase64Values returns a copy of data with the given keys base64-encoded
+// again. Keys removed in the editor are skipped.
+func encodeBase64Values(data map[string]string, keys []string) map[string]string {
+	result := make(map[string]string, len(data))
+	for k, v := range data {
+		result[k] = v
+	}
+	for _, k := range keys {
+		if v, ok := data[k]; ok {
+			result[k] = base64.StdEncoding.EncodeToString([]byte(v))
+		}
+	}
+	return result
+}
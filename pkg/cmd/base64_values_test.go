@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestIsStoredBase64(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{base64.StdEncoding.EncodeToString([]byte("s3cr3t-password")), true},
+		{base64.StdEncoding.EncodeToString([]byte(`{"auths":{}}`)), true},
+		{"password", false},
+		{"abcdefgh", false},
+		{base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0x00, 0x01, 0x02, 0x03}), false},
+		{"plain text value", false},
+		{" " + base64.StdEncoding.EncodeToString([]byte("s3cr3t-password")), false},
+	}
+
+	for _, tt := range tests {
+		if got := isStoredBase64(tt.value); got != tt.want {
+			t.Errorf("isStoredBase64(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBase64ValuesRoundTrip(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("s3cr3t-password"))
+	data := map[string]string{"token": encoded, "password": "password", "gone": encoded}
+
+	keys := decodeBase64Values(data)
+	if want := []string{"gone", "token"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("decodeBase64Values() keys = %q, want %q", keys, want)
+	}
+	if data["token"] != "s3cr3t-password" || data["password"] != "password" {
+		t.Fatalf("decoded data = %q", data)
+	}
+
+	// gone is removed in the editor and token changed
+	delete(data, "gone")
+	data["token"] = "rotated"
+	got := encodeBase64Values(data, keys)
+	want := map[string]string{"token": base64.StdEncoding.EncodeToString([]byte("rotated")), "password": "password"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("encodeBase64Values() = %q, want %q", got, want)
+	}
+}
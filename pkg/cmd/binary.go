@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// binaryMarker is the comment placed above binary values in the edit buffer
+const binaryMarker = "binary value, shown base64-encoded; keep it base64-encoded"
+
+// binaryKeys returns the sorted keys whose values are not valid UTF-8.
+// Such values cannot round-trip through the YAML buffer as text.
+func binaryKeys(data map[string]string) []string {
+	var keys []string
+	for k, v := range data {
+		if !utf8.ValidString(v) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// encodeBinaryValues returns a copy of data with the given keys base64-encoded
+func encodeBinaryValues(data map[string]string, keys []string) map[string]string {
+	view := make(map[string]string, len(data))
+	for k, v := range data {
+		view[k] = v
+	}
+	for _, k := range keys {
+		view[k] = base64.StdEncoding.EncodeToString([]byte(data[k]))
+	}
+	return view
+}
+
+// decodeBinaryValues decodes the given keys of the edited buffer back to raw
+// bytes. Keys removed in the editor are skipped.
+func decodeBinaryValues(edited map[string]string, keys []string) error {
+	for _, k := range keys {
+		v, ok := edited[k]
+		if !ok {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(v), ""))
+		if err != nil {
+			return fmt.Errorf("value of binary key %q must stay base64-encoded: %w", k, err)
+		}
+		edited[k] = string(raw)
+	}
+	return nil
+}
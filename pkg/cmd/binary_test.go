@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBinaryValueRoundTrip(t *testing.T) {
+	secret := testSecret(map[string]string{"blob": "\xff\xfe", "password": "old"})
+	original := map[string][]byte{"blob": {0xff, 0xfe}, "password": []byte("old")}
+	decoded := decodeData(secret)
+
+	o, _, _, _ := newTestOptions(t)
+	content, err := o.createEditContent(decoded)
+	if err != nil {
+		t.Fatalf("createEditContent() error = %v", err)
+	}
+
+	// A comment makes the file modified while keeping every value
+	path := filepath.Join(t.TempDir(), "buffer.yaml")
+	if err := os.WriteFile(path, []byte(content+"# touched\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	edited, err := o.readEdited(path, content, decoded)
+	if err != nil {
+		t.Fatalf("readEdited() error = %v", err)
+	}
+	if edited["blob"] != "\xff\xfe" {
+		t.Fatalf("edited blob = %q, want the original bytes", edited["blob"])
+	}
+
+	cs := o.changes(decoded, edited)
+	if len(cs.Added)+len(cs.Changed)+len(cs.Removed) != 0 {
+		t.Errorf("changes = %+v, want none", cs)
+	}
+	writeChangeSet(secret, cs, edited)
+	for k, want := range original {
+		if !bytes.Equal(secret.Data[k], want) {
+			t.Errorf("data %s = %x, want %x", k, secret.Data[k], want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// renderYAMLBuffer renders the edit buffer body as a YAML mapping. Secret data
+// is a map and the API server returns its keys sorted, so keys are written in
+// that same sorted order; the layout is the same every time a secret is opened.
+// binaryMarker is placed above each of the binary keys. An empty map renders
+// as an empty body rather than "{}", so keys can simply be typed in.
+func renderYAMLBuffer(data map[string]string, binary []string) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	mapping, err := stringMapNode(data, binary)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(mapping)
+}
+
+// stringMapNode builds a YAML mapping of data with sorted keys, placing
+// binaryMarker above each of the binary keys
+func stringMapNode(data map[string]string, binary []string) (*yaml.Node, error) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	marked := make(map[string]bool, len(binary))
+	for _, k := range binary {
+		marked[k] = true
+	}
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, k := range keys {
+		var key, value yaml.Node
+		if err := key.Encode(k); err != nil {
+			return nil, err
+		}
+		if err := value.Encode(data[k]); err != nil {
+			return nil, err
+		}
+		if marked[k] {
+			key.HeadComment = binaryMarker
+		}
+		mapping.Content = append(mapping.Content, &key, &value)
+	}
+	return mapping, nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderYAMLBufferStableOrder(t *testing.T) {
+	data := make(map[string]string)
+	for _, k := range strings.Fields("zeta alpha mid Beta _x 10 2 a.b a-b") {
+		data[k] = "value of " + k
+	}
+
+	first, err := renderYAMLBuffer(data, nil)
+	if err != nil {
+		t.Fatalf("renderYAMLBuffer() error = %v", err)
+	}
+	// Map iteration order is random, so repeated renders catch any reliance on it
+	for i := 0; i < 20; i++ {
+		again, err := renderYAMLBuffer(data, nil)
+		if err != nil {
+			t.Fatalf("renderYAMLBuffer() error = %v", err)
+		}
+		if string(again) != string(first) {
+			t.Fatalf("render %d =\n%s\nwant\n%s", i+2, again, first)
+		}
+	}
+
+	var keys []string
+	for _, line := range strings.Split(strings.TrimSpace(string(first)), "\n") {
+		keys = append(keys, strings.TrimSpace(strings.SplitN(line, ":", 2)[0]))
+	}
+	want := `"10" "2" Beta _x a-b a.b alpha mid zeta`
+	if got := strings.Join(keys, " "); got != want {
+		t.Errorf("key order = %s, want %s", got, want)
+	}
+}
+
+func TestRenderYAMLBufferEmpty(t *testing.T) {
+	got, err := renderYAMLBuffer(map[string]string{}, nil)
+	if err != nil || len(got) != 0 {
+		t.Errorf("renderYAMLBuffer(empty) = %q, %v; want an empty body", got, err)
+	}
+}
+
+func TestJSONBufferRoundTrip(t *testing.T) {
+	data := map[string]string{
+		"quotes":    `say "hi"`,
+		"backslash": `C:\Users\admin\n`,
+		"multiline": "line1\nline2\n",
+		"unicode":   "pässwörd ✓ 日本",
+		"html":      "<a href='x'>&</a>",
+		"empty":     "",
+	}
+
+	o, _, _, _ := newTestOptions(t)
+	o.format = formatJSON
+	content, err := o.createEditContent(data)
+	if err != nil {
+		t.Fatalf("createEditContent() error = %v", err)
+	}
+	if !strings.Contains(content, `"html": "<a href='x'>&</a>"`) {
+		t.Errorf("buffer escapes HTML characters:\n%s", content)
+	}
+
+	got, err := parseJSONContent([]byte(content))
+	if err != nil {
+		t.Fatalf("parseJSONContent() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("round trip = %q, want %q", got, data)
+	}
+}
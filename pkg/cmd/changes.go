@@ -0,0 +1,90 @@
+package cmd
+
+import "sort"
+
+// changeSet lists the keys affected by an edit, by kind of change
+type changeSet struct {
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+// computeChanges compares the original and edited values and returns the
+// sorted keys that were added, changed, or removed
+func computeChanges(original, edited map[string]string) changeSet {
+	var cs changeSet
+	for k, newVal := range edited {
+		oldVal, ok := original[k]
+		switch {
+		case !ok:
+			cs.Added = append(cs.Added, k)
+		case oldVal != newVal:
+			cs.Changed = append(cs.Changed, k)
+		}
+	}
+	for k := range original {
+		if _, ok := edited[k]; !ok {
+			cs.Removed = append(cs.Removed, k)
+		}
+	}
+
+	sort.Strings(cs.Added)
+	sort.Strings(cs.Changed)
+	sort.Strings(cs.Removed)
+	return cs
+}
+
+// changes returns the change set that applyChanges will write. When editing
+// KEY or --key keys, only those keys are considered, since other keys are
+// never touched.
+func (o *EditSecretOptions) changes(original, edited map[string]string) changeSet {
+	keys := o.keys
+	if o.key != "" {
+		keys = []string{o.key}
+	}
+	if len(keys) == 0 {
+		return computeChanges(original, edited)
+	}
+
+	var cs changeSet
+	for _, k := range keys {
+		if newVal, ok := edited[k]; ok && newVal != original[k] {
+			cs.Changed = append(cs.Changed, k)
+		}
+	}
+	sort.Strings(cs.Changed)
+	return cs
+}
+
+// without returns a copy of the change set leaving out the given keys. It is
+// used for display only; what gets applied is never filtered.
+func (cs changeSet) without(ignore []string) changeSet {
+	if len(ignore) == 0 {
+		return cs
+	}
+
+	skip := make(map[string]bool, len(ignore))
+	for _, k := range ignore {
+		skip[k] = true
+	}
+	filter := func(keys []string) []string {
+		var kept []string
+		for _, k := range keys {
+			if !skip[k] {
+				kept = append(kept, k)
+			}
+		}
+		return kept
+	}
+
+	return changeSet{
+		Added:   filter(cs.Added),
+		Changed: filter(cs.Changed),
+		Removed: filter(cs.Removed),
+	}
+}
+
+// empty reports whether the change set has no keys
+func (cs changeSet) empty() bool {
+	return len(cs.Added)+len(cs.Changed)+len(cs.Removed) == 0
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// testConfigFlags returns config flags pointing at server, without reading
+// the user's kubeconfig
+func testConfigFlags(server string) *genericclioptions.ConfigFlags {
+	configFlags := genericclioptions.NewConfigFlags(false)
+	configFlags.APIServer = &server
+	return configFlags
+}
+
+func TestNewRESTConfigUserAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{"default", "", "kubectl-edit-secret/" + Version},
+		{"--user-agent", "ci-rotation/1.0", "ci-rotation/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, _, _ := newTestOptions(t)
+			restConfig, err := newRESTConfig(testConfigFlags("https://127.0.0.1:6443"), tt.userAgent, o.streams)
+			if err != nil {
+				t.Fatalf("newRESTConfig() error = %v", err)
+			}
+			if restConfig.UserAgent != tt.want {
+				t.Errorf("UserAgent = %q, want %q", restConfig.UserAgent, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRESTConfigImpersonation(t *testing.T) {
+	configFlags := testConfigFlags("https://127.0.0.1:6443")
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	configFlags.AddFlags(flags)
+	if err := flags.Parse([]string{"--as=jane", "--as-uid=1234", "--as-group=ops"}); err != nil {
+		t.Fatal(err)
+	}
+
+	o, _, _, _ := newTestOptions(t)
+	restConfig, err := newRESTConfig(configFlags, "", o.streams)
+	if err != nil {
+		t.Fatalf("newRESTConfig() error = %v", err)
+	}
+	impersonate := restConfig.Impersonate
+	if impersonate.UserName != "jane" || impersonate.UID != "1234" || len(impersonate.Groups) != 1 || impersonate.Groups[0] != "ops" {
+		t.Errorf("Impersonate = %+v, want jane/1234 in group ops", impersonate)
+	}
+}
+
+func TestNewClientsetPrintsWarnings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Warning", `299 - "secret db is deprecated"`)
+		w.Header().Set("Content-Type", "application/json")
+		secret := testSecret(map[string]string{"password": "old"})
+		secret.APIVersion, secret.Kind = "v1", "Secret"
+		json.NewEncoder(w).Encode(secret)
+	}))
+	defer srv.Close()
+
+	o, _, _, errOut := newTestOptions(t)
+	clientset, err := newClientset(testConfigFlags(srv.URL), "", o.streams)
+	if err != nil {
+		t.Fatalf("newClientset() error = %v", err)
+	}
+	// Repeated warnings are printed once
+	for i := 0; i < 2; i++ {
+		if _, err := clientset.CoreV1().Secrets("default").Get(context.Background(), "db", metav1.GetOptions{}); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	if got := errOut.String(); got != "Warning: secret db is deprecated\n" {
+		t.Errorf("stderr = %q, want the API warning once", got)
+	}
+}
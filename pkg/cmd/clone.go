@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// validateClone checks the flags combined with --to
+func (o *EditSecretOptions) validateClone() error {
+	if o.cloneTo == "" {
+		return nil
+	}
+	if o.cloneTo == o.secretName {
+		return fmt.Errorf("--to must name a different secret than %s", o.secretName)
+	}
+	if o.create || o.forceRecreate || o.serverSide || o.expectRV != "" {
+		return fmt.Errorf("--to cannot be combined with --create, --force-recreate, --server-side, or --expect-resource-version")
+	}
+	if o.record || o.snapshot || o.emitEvent {
+		return fmt.Errorf("--to cannot be combined with --record, --snapshot, or --emit-event")
+	}
+	if o.list || o.get || o.renderBuffer || o.exportEnv || o.showPrevious {
+		return fmt.Errorf("--to requires an edit and cannot be combined with --list, --get, --render-buffer, --export-env, or --show-previous")
+	}
+	return nil
+}
+
+// checkCloneTarget fails before the editor opens if the --to secret already
+// exists and --overwrite is not set, so no edits are lost
+func (o *EditSecretOptions) checkCloneTarget(ctx context.Context) error {
+	if o.cloneTo == "" || o.overwrite {
+		return nil
+	}
+	_, err := o.clientset.CoreV1().Secrets(o.namespace).Get(ctx, o.cloneTo, metav1.GetOptions{})
+	if err == nil {
+		return fmt.Errorf("secret %s already exists in namespace %s; pass --overwrite to replace it", o.cloneTo, o.namespace)
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to check secret %s: %w", o.cloneTo, o.forbiddenError("get", err))
+	}
+	return nil
+}
+
+// cloneSecret returns a new secret named by --to with the type, labels,
+// annotations, and data of the edited source. Annotations describing the
+// source object, such as the last-applied configuration, are not copied.
+func (o *EditSecretOptions) cloneSecret(source *corev1.Secret) *corev1.Secret {
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        o.cloneTo,
+			Namespace:   o.namespace,
+			Labels:      maps.Clone(source.Labels),
+			Annotations: maps.Clone(source.Annotations),
+		},
+		Type:      source.Type,
+		Immutable: source.Immutable,
+		Data:      maps.Clone(source.Data),
+	}
+	if o.secretType != "" {
+		target.Type = corev1.SecretType(o.secretType)
+	}
+	for k := range hiddenAnnotations {
+		delete(target.Annotations, k)
+	}
+	return target
+}
+
+// writeClone creates the --to secret, or replaces an existing one with
+// --overwrite. The source secret is never changed.
+func (o *EditSecretOptions) writeClone(ctx context.Context, target *corev1.Secret) (*corev1.Secret, error) {
+	opts := metav1.CreateOptions{}
+	if o.dryRun == dryRunServer {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	created, err := o.clientset.CoreV1().Secrets(o.namespace).Create(ctx, target, opts)
+	if err == nil {
+		return created, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create secret %s: %w", o.cloneTo, o.forbiddenError("create", err))
+	}
+	if !o.overwrite {
+		return nil, fmt.Errorf("secret %s was created by someone else while you were editing; pass --overwrite to replace it", o.cloneTo)
+	}
+
+	existing, err := o.clientset.CoreV1().Secrets(o.namespace).Get(ctx, o.cloneTo, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", o.cloneTo, o.forbiddenError("get", err))
+	}
+	if existing.Type != target.Type {
+		return nil, fmt.Errorf("secret %s has type %s, which cannot be changed to %s; delete it first", o.cloneTo, existing.Type, target.Type)
+	}
+	existing.Labels = target.Labels
+	existing.Annotations = target.Annotations
+	existing.Data = target.Data
+	existing.StringData = nil
+	o.overwroteClone = true
+
+	updated, err := o.clientset.CoreV1().Secrets(o.namespace).Update(ctx, existing, o.updateOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret %s: %w", o.cloneTo, o.forbiddenError("update", err))
+	}
+	return updated, nil
+}
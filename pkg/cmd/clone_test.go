@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunCloneTo(t *testing.T) {
+	o, clientset, out, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "user": "admin"}))
+	o.cloneTo = "db-staging"
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := out.String(); got != "secret/db-staging created from secret/db\n" {
+		t.Errorf("stdout = %q, want the clone line", got)
+	}
+	if got := storedSecretData(t, clientset, "db-staging"); got["password"] != "new" || got["user"] != "admin" {
+		t.Errorf("clone data = %q, want the edited values", got)
+	}
+	if got := storedData(t, clientset)["password"]; got != "old" {
+		t.Errorf("source password = %q, want it unchanged", got)
+	}
+}
+
+func TestRunCloneToExistingTarget(t *testing.T) {
+	target := testSecret(map[string]string{"password": "staging"})
+	target.Name = "db-staging"
+
+	t.Run("without --overwrite", func(t *testing.T) {
+		// newTestOptions fails the test if the editor is started
+		o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}), target.DeepCopy())
+		o.cloneTo = "db-staging"
+
+		err := o.Run()
+		if err == nil || !strings.Contains(err.Error(), "secret db-staging already exists in namespace default; pass --overwrite to replace it") {
+			t.Fatalf("Run() error = %v, want the overwrite guard", err)
+		}
+		if got := storedSecretData(t, clientset, "db-staging")["password"]; got != "staging" {
+			t.Errorf("target password = %q, want it unchanged", got)
+		}
+	})
+
+	t.Run("with --overwrite", func(t *testing.T) {
+		o, clientset, out, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}), target.DeepCopy())
+		o.cloneTo = "db-staging"
+		o.overwrite = true
+		fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+		if err := o.Run(); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if got := out.String(); got != "secret/db-staging replaced from secret/db\n" {
+			t.Errorf("stdout = %q, want the replaced line", got)
+		}
+		if got := storedSecretData(t, clientset, "db-staging")["password"]; got != "new" {
+			t.Errorf("target password = %q, want %q", got, "new")
+		}
+	})
+}
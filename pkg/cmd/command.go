@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+)
+
+// splitCommand splits a command line into words like a POSIX shell, without
+// expansions. Single quotes keep everything literally; inside double quotes a
+// backslash only escapes '"' and '\'. Outside quotes a backslash escapes
+// whitespace, quotes, and '\', and is kept as is before anything else, so
+// unquoted Windows paths like C:\tools\vim.exe keep their backslashes.
+func splitCommand(s string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+	)
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+
+		case r == '\'':
+			inWord = true
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			current.WriteString(string(runes[i+1 : end]))
+			i = end
+
+		case r == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '"' {
+					closed = true
+					break
+				}
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					i++
+				}
+				current.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, errors.New("unterminated double quote")
+			}
+
+		case r == '\\' && i+1 < len(runes) && strings.ContainsRune(" \t'\"\\", runes[i+1]):
+			inWord = true
+			i++
+			current.WriteRune(runes[i])
+
+		default:
+			inWord = true
+			current.WriteRune(r)
+		}
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// indexRune returns the index of the first r in runes at or after from, or -1
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
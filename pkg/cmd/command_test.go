@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr string
+	}{
+		{"plain", "vim", []string{"vim"}, ""},
+		{"arguments", "code --wait -n", []string{"code", "--wait", "-n"}, ""},
+		{"double-quoted path", `"/path with space/editor" --wait`, []string{"/path with space/editor", "--wait"}, ""},
+		{"single-quoted path", `'/path with space/editor' --wait`, []string{"/path with space/editor", "--wait"}, ""},
+		{"escaped space", `/path\ with\ space/editor`, []string{"/path with space/editor"}, ""},
+		{"escaped quote in double quotes", `sh -c "echo \"hi\""`, []string{"sh", "-c", `echo "hi"`}, ""},
+		{"extra whitespace", "  nano\t-w  ", []string{"nano", "-w"}, ""},
+		{"empty quotes", `editor ""`, []string{"editor", ""}, ""},
+		{"unterminated double quote", `"/path with space/editor --wait`, nil, "unterminated double quote"},
+		{"unterminated single quote", `'/path --wait`, nil, "unterminated single quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitCommand(tt.command)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("splitCommand() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitCommand() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
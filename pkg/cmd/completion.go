@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// completeArgs completes secret names for SECRET_NAME and the secret's keys
+// for KEY. Complete has not run yet, so a client is built from the config
+// flags, and any error just yields no suggestions.
+func (o *EditSecretOptions) completeArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	namespace, _, err := o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	streams := genericclioptions.IOStreams{In: cmd.InOrStdin(), Out: io.Discard, ErrOut: io.Discard}
+	clientset, err := newClientset(o.configFlags, o.userAgent, streams)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx := context.Background()
+	var candidates []string
+	if len(args) == 0 {
+		secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, s := range secrets.Items {
+			candidates = append(candidates, s.Name)
+		}
+	} else {
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, args[0], metav1.GetOptions{})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		candidates = sortedKeys(secret.Data)
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, toComplete) {
+			matches = append(matches, c)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
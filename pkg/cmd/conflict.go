@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// Policies for --on-conflict
+const (
+	// conflictAbort fails without writing. Nothing is lost on the server,
+	// but the user's edits are discarded.
+	conflictAbort = "abort"
+	// conflictOverwrite writes the user's full edit over the latest version.
+	// Any key changed by someone else since the secret was read is reverted.
+	conflictOverwrite = "overwrite"
+	// conflictMerge re-applies only the keys the user changed onto the latest
+	// version. Other keys keep their new server values; if a key was changed
+	// on both sides, nothing is written.
+	conflictMerge = "merge"
+)
+
+// resolveConflict handles an update rejected because the secret changed on the
+// server after it was read, according to the --on-conflict policy. base holds
+// the data as it was read before editing.
+func (o *EditSecretOptions) resolveConflict(ctx context.Context, base map[string][]byte, secret *corev1.Secret, cs changeSet, edited map[string]string) (*corev1.Secret, error) {
+	if o.onConflict == conflictAbort {
+		return nil, fmt.Errorf("secret %s was modified on the server while you were editing; no changes were applied. Re-run the command to edit the latest version", o.secretName)
+	}
+
+	latest, err := o.clientset.CoreV1().Secrets(o.namespace).Get(ctx, o.secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest version of secret %s: %w", o.secretName, err)
+	}
+
+	target := secret
+	if o.onConflict == conflictMerge {
+		if key, ok := conflictingKey(base, latest.Data, cs, edited); ok {
+			return nil, fmt.Errorf("key %q of secret %s was also changed on the server while you were editing; no changes were applied. Re-run the command to edit the latest version", key, o.secretName)
+		}
+		if o.snapshot {
+			if err := recordSnapshot(latest); err != nil {
+				return nil, err
+			}
+		}
+		if err := o.applyChangeSet(latest, cs, edited); err != nil {
+			return nil, err
+		}
+		target = latest
+	}
+	target.ResourceVersion = latest.ResourceVersion
+
+	updated, err := o.clientset.CoreV1().Secrets(o.namespace).Update(ctx, target, o.updateOptions())
+	if apierrors.IsConflict(err) {
+		return nil, fmt.Errorf("secret %s is being modified concurrently; no changes were applied. Re-run the command to edit the latest version", o.secretName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret: %w", o.forbiddenError("update", err))
+	}
+
+	fmt.Fprintf(o.streams.ErrOut, "Warning: secret %s changed on the server while you were editing; applied with --on-conflict=%s\n", o.secretName, o.onConflict)
+	return updated, nil
+}
+
+// retryConflict handles a conflict for --retry-on-conflict. It re-fetches the
+// secret, re-applies the change set onto it, and retries the update with
+// backoff. base holds the data as it was read before editing; if a key in the
+// change set was changed on the server to a different value than the user's,
+// it aborts instead of overwriting that change.
+func (o *EditSecretOptions) retryConflict(ctx context.Context, base map[string][]byte, cs changeSet, edited map[string]string) (*corev1.Secret, error) {
+	var (
+		updated  *corev1.Secret
+		attempts int
+	)
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		attempts++
+		latest, err := o.clientset.CoreV1().Secrets(o.namespace).Get(ctx, o.secretName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get latest version of secret %s: %w", o.secretName, err)
+		}
+		if key, ok := conflictingKey(base, latest.Data, cs, edited); ok {
+			return fmt.Errorf("key %q of secret %s was also changed on the server while you were editing; no changes were applied. Re-run the command to edit the latest version", key, o.secretName)
+		}
+
+		if o.snapshot {
+			if err := recordSnapshot(latest); err != nil {
+				return err
+			}
+		}
+		if err := o.applyChangeSet(latest, cs, edited); err != nil {
+			return err
+		}
+		if updated, err = o.clientset.CoreV1().Secrets(o.namespace).Update(ctx, latest, o.updateOptions()); err != nil {
+			return fmt.Errorf("failed to update secret: %w", o.forbiddenError("update", err))
+		}
+		return nil
+	})
+	if apierrors.IsConflict(err) {
+		return nil, fmt.Errorf("secret %s is still being modified concurrently after %d attempts; no changes were applied. Re-run the command to edit the latest version", o.secretName, attempts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(o.streams.ErrOut, "Warning: secret %s changed on the server while you were editing; your changes were re-applied to the latest version\n", o.secretName)
+	return updated, nil
+}
+
+// conflictingKey returns the first key in the change set whose server value
+// moved away from base to something other than the user's value
+func conflictingKey(base, latest map[string][]byte, cs changeSet, edited map[string]string) (string, bool) {
+	check := func(k string, want []byte, remove bool) bool {
+		before, hadBefore := base[k]
+		now, hasNow := latest[k]
+		if hadBefore == hasNow && bytes.Equal(before, now) {
+			return false
+		}
+		if remove {
+			return hasNow
+		}
+		return !hasNow || !bytes.Equal(now, want)
+	}
+
+	for _, k := range cs.Added {
+		if check(k, []byte(edited[k]), false) {
+			return k, true
+		}
+	}
+	for _, k := range cs.Changed {
+		if check(k, []byte(edited[k]), false) {
+			return k, true
+		}
+	}
+	for _, k := range cs.Removed {
+		if check(k, nil, true) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// resourceVersionMismatch reports that the secret is no longer at the
+// version given with --expect-resource-version
+func (o *EditSecretOptions) resourceVersionMismatch(actual string) error {
+	if actual == "" {
+		return fmt.Errorf("secret %s is no longer at resourceVersion %s; no changes were applied", o.secretName, o.expectRV)
+	}
+	return fmt.Errorf("secret %s is at resourceVersion %s, expected %s; no changes were applied", o.secretName, actual, o.expectRV)
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// conflictOnce makes the first update of the secret fail with a conflict,
+// after changing it on the server with serverChange as another client would
+func conflictOnce(t *testing.T, clientset *fake.Clientset, serverChange func(*corev1.Secret)) {
+	t.Helper()
+
+	conflicted := false
+	clientset.PrependReactor("update", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicted {
+			return false, nil, nil
+		}
+		conflicted = true
+
+		gvr := corev1.SchemeGroupVersion.WithResource("secrets")
+		obj, err := clientset.Tracker().Get(gvr, "default", "db")
+		if err != nil {
+			t.Fatalf("getting stored secret: %v", err)
+		}
+		latest := obj.(*corev1.Secret).DeepCopy()
+		serverChange(latest)
+		latest.ResourceVersion = "2"
+		if err := clientset.Tracker().Update(gvr, latest, "default"); err != nil {
+			t.Fatalf("changing stored secret: %v", err)
+		}
+		return true, nil, apierrors.NewConflict(corev1.Resource("secrets"), "db", nil)
+	})
+}
+
+func TestApplyChangesOnConflict(t *testing.T) {
+	tests := []struct {
+		policy  string
+		wantErr string
+		want    map[string]string
+	}{
+		{conflictAbort, "was modified on the server", map[string]string{"password": "old", "user": "root"}},
+		{conflictOverwrite, "", map[string]string{"password": "new", "user": "admin"}},
+		{conflictMerge, "", map[string]string{"password": "new", "user": "root"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			live := testSecret(map[string]string{"password": "old", "user": "admin"})
+			o, clientset, _, errOut := newTestOptions(t, live)
+			o.onConflict = tt.policy
+			conflictOnce(t, clientset, func(s *corev1.Secret) { s.Data["user"] = []byte("root") })
+
+			original := map[string]string{"password": "old", "user": "admin"}
+			edited := map[string]string{"password": "new", "user": "admin"}
+			_, err := o.applyChanges(context.Background(), live.DeepCopy(), original, edited)
+
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyChanges() error = %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("applyChanges() error = %v", err)
+			case !strings.Contains(errOut.String(), "--on-conflict="+tt.policy):
+				t.Errorf("stderr = %q, want a warning naming the policy", errOut.String())
+			}
+
+			got := storedData(t, clientset)
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("stored %s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestRunConcurrentModification(t *testing.T) {
+	tests := []struct {
+		policy  string
+		wantErr bool
+		want    map[string]string
+	}{
+		{conflictAbort, true, map[string]string{"password": "old", "user": "root"}},
+		{conflictOverwrite, false, map[string]string{"password": "new", "user": "admin"}},
+		// merge keeps the server's change to the key that was not edited
+		{conflictMerge, false, map[string]string{"password": "new", "user": "root"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "user": "admin"}))
+			o.onConflict = tt.policy
+			fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+			conflictOnce(t, clientset, func(s *corev1.Secret) { s.Data["user"] = []byte("root") })
+
+			if err := o.Run(); (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, want error %v", err, tt.wantErr)
+			}
+			got := storedData(t, clientset)
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("stored %s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestConflictingKey(t *testing.T) {
+	base := map[string][]byte{"password": []byte("old"), "user": []byte("admin"), "gone": []byte("x")}
+	tests := []struct {
+		name   string
+		latest map[string][]byte
+		cs     changeSet
+		edited map[string]string
+		want   string
+	}{
+		{
+			name:   "other key changed",
+			latest: map[string][]byte{"password": []byte("old"), "user": []byte("root"), "gone": []byte("x")},
+			cs:     changeSet{Changed: []string{"password"}},
+			edited: map[string]string{"password": "new"},
+		},
+		{
+			name:   "edited key changed",
+			latest: map[string][]byte{"password": []byte("remote"), "user": []byte("admin"), "gone": []byte("x")},
+			cs:     changeSet{Changed: []string{"password"}},
+			edited: map[string]string{"password": "new"},
+			want:   "password",
+		},
+		{
+			name:   "edited key changed to the same value",
+			latest: map[string][]byte{"password": []byte("new"), "user": []byte("admin"), "gone": []byte("x")},
+			cs:     changeSet{Changed: []string{"password"}},
+			edited: map[string]string{"password": "new"},
+		},
+		{
+			name:   "added key added remotely",
+			latest: map[string][]byte{"password": []byte("old"), "user": []byte("admin"), "gone": []byte("x"), "token": []byte("t1")},
+			cs:     changeSet{Added: []string{"token"}},
+			edited: map[string]string{"token": "t2"},
+			want:   "token",
+		},
+		{
+			name:   "removed key changed remotely",
+			latest: map[string][]byte{"password": []byte("old"), "user": []byte("admin"), "gone": []byte("y")},
+			cs:     changeSet{Removed: []string{"gone"}},
+			want:   "gone",
+		},
+		{
+			name:   "removed key removed remotely",
+			latest: map[string][]byte{"password": []byte("old"), "user": []byte("admin")},
+			cs:     changeSet{Removed: []string{"gone"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := conflictingKey(base, tt.latest, tt.cs, tt.edited)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("conflictingKey() = %q, %v; want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunRetryOnConflict(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  func(*corev1.Secret)
+		wantErr string
+		want    map[string]string
+	}{
+		{
+			name:   "other key changed",
+			remote: func(s *corev1.Secret) { s.Data["user"] = []byte("root") },
+			want:   map[string]string{"password": "new", "user": "root"},
+		},
+		{
+			name:    "edited key changed",
+			remote:  func(s *corev1.Secret) { s.Data["password"] = []byte("remote") },
+			wantErr: `key "password" of secret db was also changed on the server`,
+			want:    map[string]string{"password": "remote", "user": "admin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "user": "admin"}))
+			o.retryOnConflict = true
+			fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+			conflictOnce(t, clientset, tt.remote)
+
+			err := o.Run()
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Run() error = %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("Run() error = %v", err)
+			}
+			got := storedData(t, clientset)
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("stored %s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyChangesMergeRejectsKeyChangedOnBothSides(t *testing.T) {
+	live := testSecret(map[string]string{"password": "old", "user": "admin"})
+	o, clientset, _, errOut := newTestOptions(t, live)
+	conflictOnce(t, clientset, func(s *corev1.Secret) { s.Data["password"] = []byte("theirs") })
+
+	original := map[string]string{"password": "old", "user": "admin"}
+	edited := map[string]string{"password": "mine", "user": "admin"}
+	_, err := o.applyChanges(context.Background(), live.DeepCopy(), original, edited)
+	if err == nil || !strings.Contains(err.Error(), `key "password" of secret db was also changed on the server`) {
+		t.Fatalf("applyChanges() error = %v, want the both-sides conflict", err)
+	}
+	if got := storedData(t, clientset)["password"]; got != "theirs" {
+		t.Errorf("stored password = %q, want the server's value kept", got)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("stderr = %q, want no applied warning", errOut.String())
+	}
+}
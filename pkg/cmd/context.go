@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultNamespace falls back to the "default" namespace when neither the
+// kubeconfig nor --namespace set one, which can happen with minimal or
+// in-cluster configs
+func (o *EditSecretOptions) defaultNamespace() {
+	if o.namespace != "" {
+		return
+	}
+	o.namespace = metav1.NamespaceDefault
+	if !o.allNamespaces {
+		fmt.Fprintf(o.streams.ErrOut, "No namespace is set in the kubeconfig or with --namespace, using %q.\n", o.namespace)
+	}
+}
+
+// resolveContext records the kubeconfig context and API server in use, so
+// the edit buffer can show which cluster is being edited, and enforces
+// --require-context
+func (o *EditSecretOptions) resolveContext() error {
+	rawConfig, err := o.configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	o.contextName = rawConfig.CurrentContext
+	if o.configFlags.Context != nil && *o.configFlags.Context != "" {
+		o.contextName = *o.configFlags.Context
+	}
+
+	restConfig, err := o.configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("failed to create REST config: %w", err)
+	}
+	o.server = restConfig.Host
+
+	if o.requireContext != "" && o.contextName != o.requireContext {
+		return fmt.Errorf("current context is %q, but --require-context=%s; no changes were made", o.contextName, o.requireContext)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import "testing"
+
+func TestDefaultNamespace(t *testing.T) {
+	tests := []struct {
+		name          string
+		namespace     string
+		allNamespaces bool
+		want          string
+		wantNotice    bool
+	}{
+		{"unset", "", false, "default", true},
+		{"unset with --all-namespaces", "", true, "default", false},
+		{"set", "prod", false, "prod", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, out, errOut := newTestOptions(t)
+			o.namespace = tt.namespace
+			o.allNamespaces = tt.allNamespaces
+
+			o.defaultNamespace()
+			if o.namespace != tt.want {
+				t.Errorf("namespace = %q, want %q", o.namespace, tt.want)
+			}
+			notice := "No namespace is set in the kubeconfig or with --namespace, using \"default\".\n"
+			if got := errOut.String() == notice; got != tt.wantNotice {
+				t.Errorf("stderr = %q, want notice %v", errOut.String(), tt.wantNotice)
+			}
+			if out.Len() != 0 {
+				t.Errorf("stdout = %q, want the notice kept off stdout", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newSecret returns the empty secret edited when --create finds no secret
+func (o *EditSecretOptions) newSecret() *corev1.Secret {
+	secretType := corev1.SecretTypeOpaque
+	if o.secretType != "" {
+		secretType = corev1.SecretType(o.secretType)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      o.secretName,
+			Namespace: o.namespace,
+		},
+		Type: secretType,
+		Data: map[string][]byte{},
+	}
+}
+
+// createSecret creates the secret started with --create
+func (o *EditSecretOptions) createSecret(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+	opts := metav1.CreateOptions{}
+	if o.dryRun == dryRunServer {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	created, err := o.clientset.CoreV1().Secrets(o.namespace).Create(ctx, secret, opts)
+	if apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("secret %s was created by someone else while you were editing; no changes were applied. Re-run the command to edit it", o.secretName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret: %w", o.forbiddenError("create", err))
+	}
+	return created, nil
+}
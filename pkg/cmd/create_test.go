@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRunCreate(t *testing.T) {
+	o, clientset, out, _ := newTestOptions(t)
+	o.create = true
+	fakeEditor(o, 0, func(_ int, content string) string {
+		return content + "password: s3cr3t\nuser: admin\n"
+	})
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var created *corev1.Secret
+	for _, action := range clientset.Actions() {
+		if action.Matches("create", "secrets") {
+			created = action.(k8stesting.CreateAction).GetObject().(*corev1.Secret)
+		}
+	}
+	if created == nil {
+		t.Fatal("no create action")
+	}
+	if created.Name != "db" || created.Namespace != "default" || created.Type != corev1.SecretTypeOpaque {
+		t.Errorf("created %s/%s of type %s, want default/db of type Opaque", created.Namespace, created.Name, created.Type)
+	}
+	if got := decodeData(created); len(got) != 2 || got["password"] != "s3cr3t" || got["user"] != "admin" {
+		t.Errorf("created data = %q, want the edited values", got)
+	}
+	if out.String() != "secret/db created\n" {
+		t.Errorf("stdout = %q, want the created line", out.String())
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// beginMarkerRE and endMarkerRE match the sentinel lines wrapping each value
+// in --delimited mode
+var (
+	beginMarkerRE = regexp.MustCompile(`^### BEGIN (\S+) ###$`)
+	endMarkerRE   = regexp.MustCompile(`^### END (\S+) ###$`)
+)
+
+func beginMarker(key string) string { return fmt.Sprintf("### BEGIN %s ###", key) }
+func endMarker(key string) string   { return fmt.Sprintf("### END %s ###", key) }
+
+// renderDelimited writes each value between BEGIN and END marker lines.
+// The newline right before the END marker is part of the framing, not the
+// value, so values with and without a trailing newline round-trip exactly.
+func renderDelimited(data map[string]string) (string, error) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		v := data[k]
+		for _, line := range strings.Split(v, "\n") {
+			if strings.TrimSuffix(line, "\r") == endMarker(k) {
+				return "", fmt.Errorf("value of key %q contains its own end marker and cannot be edited in --delimited mode", k)
+			}
+		}
+
+		b.WriteString(beginMarker(k) + "\n")
+		b.WriteString(v + "\n")
+		b.WriteString(endMarker(k) + "\n")
+	}
+	return b.String(), nil
+}
+
+// parseDelimited extracts the values between BEGIN and END marker lines.
+// Outside a block only blank lines and '#' comments are allowed. A trailing
+// '\r' is ignored on marker lines, for editors that save CRLF line endings,
+// but kept in values so they round-trip exactly.
+func parseDelimited(content []byte) (map[string]string, error) {
+	result := make(map[string]string)
+
+	var (
+		key   string
+		value []string
+		open  bool
+		start int
+	)
+
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		marker := strings.TrimSuffix(line, "\r")
+
+		if open {
+			if marker == endMarker(key) {
+				result[key] = strings.Join(value, "\n")
+				open = false
+				continue
+			}
+			value = append(value, line)
+			continue
+		}
+
+		if m := beginMarkerRE.FindStringSubmatch(marker); m != nil {
+			key = m[1]
+			if _, dup := result[key]; dup {
+				return nil, fmt.Errorf("line %d: key %q appears more than once", i+1, key)
+			}
+			value = nil
+			open = true
+			start = i + 1
+			continue
+		}
+
+		if m := endMarkerRE.FindStringSubmatch(marker); m != nil {
+			return nil, fmt.Errorf("line %d: END marker for %q without a matching BEGIN", i+1, m[1])
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return nil, fmt.Errorf("line %d: content outside of BEGIN/END markers", i+1)
+		}
+	}
+
+	if open {
+		return nil, fmt.Errorf("line %d: BEGIN marker for %q has no matching END", start, key)
+	}
+	return result, nil
+}
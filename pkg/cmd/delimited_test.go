@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDelimitedRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]string
+	}{
+		{"comment lines", map[string]string{"script": "#!/bin/sh\n# not a comment\necho ok\n"}},
+		{"document separator", map[string]string{"manifest": "a: 1\n---\nb: 2"}},
+		{"tabs", map[string]string{"tsv": "a\tb\n\tindented\t"}},
+		{"empty value", map[string]string{"empty": "", "user": "admin"}},
+		{"no trailing newline", map[string]string{"password": "s3cr3t"}},
+		{"trailing newlines", map[string]string{"key": "line\n\n"}},
+		{"CRLF line endings", map[string]string{"cert": "line1\r\nline2\r\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := renderDelimited(tt.data)
+			if err != nil {
+				t.Fatalf("renderDelimited() error = %v", err)
+			}
+			got, err := parseDelimited([]byte(rendered))
+			if err != nil {
+				t.Fatalf("parseDelimited() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.data) {
+				t.Errorf("round trip = %q, want %q", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestParseDelimitedCRLFMarkers(t *testing.T) {
+	got, err := parseDelimited([]byte("### BEGIN password ###\r\ns3cr3t\r\n### END password ###\r\n"))
+	if err != nil {
+		t.Fatalf("parseDelimited() error = %v", err)
+	}
+	if want := map[string]string{"password": "s3cr3t\r"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDelimited() = %q, want %q", got, want)
+	}
+}
+
+func TestParseDelimitedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing END", "### BEGIN password ###\nold\n", `line 1: BEGIN marker for "password" has no matching END`},
+		{"duplicate key", "### BEGIN a ###\n1\n### END a ###\n### BEGIN a ###\n2\n### END a ###\n", `line 4: key "a" appears more than once`},
+		{"END without BEGIN", "### END a ###\n", `END marker for "a" without a matching BEGIN`},
+		{"content outside", "# header\nstray\n", "line 2: content outside of BEGIN/END markers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDelimited([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseDelimited() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderDelimitedRejectsOwnEndMarker(t *testing.T) {
+	_, err := renderDelimited(map[string]string{"a": "x\n### END a ###\ny"})
+	if err == nil {
+		t.Error("renderDelimited() error = nil, want an error for a value holding its end marker")
+	}
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// maxDiffCells bounds the size of the line diff table. Past it, the differing
+// middle of two values is reported as fully removed and re-added.
+const maxDiffCells = 4 << 20
+
+// diffOpKind is the kind of a line in a line diff
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffDelete
+	diffInsert
+)
+
+// diffOp is one line of a line diff
+type diffOp struct {
+	Kind diffOpKind
+	Line string
+}
+
+// splitLines splits a value into lines. A trailing newline does not start an
+// extra empty line, and an empty value has no lines.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes a line diff turning a into b, using the longest common
+// subsequence of the lines that differ between their common prefix and suffix
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{diffEqual, line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{diffEqual, line})
+	}
+	return ops
+}
+
+// diffMiddle diffs two line slices with an LCS table
+func diffMiddle(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{diffDelete, line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{diffInsert, line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{diffDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{diffInsert, b[j]})
+	}
+	return ops
+}
+
+// diffStat counts the changed keys and the inserted and deleted lines
+// across their values
+type diffStat struct {
+	Keys       int
+	Insertions int
+	Deletions  int
+}
+
+// computeDiffStat summarizes the line changes of a change set without
+// exposing any values
+func computeDiffStat(cs changeSet, original, edited map[string]string) diffStat {
+	stat := diffStat{Keys: len(cs.Added) + len(cs.Changed) + len(cs.Removed)}
+
+	for _, k := range cs.Added {
+		stat.Insertions += len(splitLines(edited[k]))
+	}
+	for _, k := range cs.Removed {
+		stat.Deletions += len(splitLines(original[k]))
+	}
+	for _, k := range cs.Changed {
+		for _, op := range diffLines(splitLines(original[k]), splitLines(edited[k])) {
+			switch op.Kind {
+			case diffInsert:
+				stat.Insertions++
+			case diffDelete:
+				stat.Deletions++
+			}
+		}
+	}
+	return stat
+}
+
+func (s diffStat) String() string {
+	return fmt.Sprintf("%d keys changed, %d insertions(+), %d deletions(-)", s.Keys, s.Insertions, s.Deletions)
+}
+
+// ANSI colors for renderDiff
+const (
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorReset  = "\x1b[0m"
+)
+
+// renderDiff prints the keys in the change set with a line diff of their
+// values: "+ key" for added, "- key" for removed, and "~ key" for changed keys.
+// With color, additions are green, removals red, and changed keys yellow.
+func renderDiff(w io.Writer, cs changeSet, original, edited map[string]string, color bool) {
+	paint := func(c, s string) string {
+		if !color {
+			return s
+		}
+		return c + s + colorReset
+	}
+
+	type entry struct {
+		key   string
+		mark  string
+		color string
+	}
+
+	entries := make([]entry, 0, len(cs.Added)+len(cs.Changed)+len(cs.Removed))
+	for _, k := range cs.Added {
+		entries = append(entries, entry{k, "+", colorGreen})
+	}
+	for _, k := range cs.Changed {
+		entries = append(entries, entry{k, "~", colorYellow})
+	}
+	for _, k := range cs.Removed {
+		entries = append(entries, entry{k, "-", colorRed})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	for _, e := range entries {
+		fmt.Fprintln(w, paint(e.color, e.mark+" "+e.key))
+		for _, op := range diffLines(splitLines(original[e.key]), splitLines(edited[e.key])) {
+			switch op.Kind {
+			case diffEqual:
+				fmt.Fprintf(w, "     %s\n", op.Line)
+			case diffDelete:
+				fmt.Fprintln(w, paint(colorRed, "    -"+op.Line))
+			case diffInsert:
+				fmt.Fprintln(w, paint(colorGreen, "    +"+op.Line))
+			}
+		}
+	}
+}
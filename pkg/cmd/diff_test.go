@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunDiffIgnore(t *testing.T) {
+	o, clientset, out, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "token": "t1"}))
+	o.diff = true
+	o.diffStat = true
+	o.force = true
+	o.diffIgnore = []string{"token"}
+	fakeEditor(o, 0, func(_ int, content string) string {
+		content = strings.Replace(content, "password: old", "password: new", 1)
+		return strings.Replace(content, "token: t1", "token: t2", 1)
+	})
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "~ password\n    -old\n    +new\n1 keys changed, 1 insertions(+), 1 deletions(-)\nsecret/db edited\n"
+	if got := out.String(); got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	stored := storedData(t, clientset)
+	if stored["password"] != "new" || stored["token"] != "t2" {
+		t.Errorf("stored data = %q, want both keys written", stored)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// validateDiffWith checks the flags combined with --diff-with
+func (o *EditSecretOptions) validateDiffWith() error {
+	if o.diffWith == "" {
+		return nil
+	}
+	if o.key != "" || o.keyPattern != "" || len(o.keys) > 0 {
+		return fmt.Errorf("--diff-with cannot be combined with KEY or --key")
+	}
+	if o.stdin || o.list || o.create || o.fromServiceAccount != "" {
+		return fmt.Errorf("--diff-with cannot be combined with --stdin, --list, --create, or --from-serviceaccount")
+	}
+	return nil
+}
+
+// runDiffWith prints a key-level diff of the decoded values of SECRET_NAME
+// and the --diff-with secret. Nothing is modified.
+func (o *EditSecretOptions) runDiffWith(ctx context.Context) error {
+	a, nameA, err := o.getDecoded(ctx, o.secretName)
+	if err != nil {
+		return err
+	}
+	b, nameB, err := o.getDecoded(ctx, o.diffWith)
+	if err != nil {
+		return err
+	}
+
+	cs := computeChanges(a, b).without(o.diffIgnore)
+	if cs.empty() {
+		fmt.Fprintf(o.streams.Out, "No differences between %s and %s.\n", nameA, nameB)
+		return nil
+	}
+
+	fmt.Fprintf(o.streams.Out, "--- %s\n+++ %s\n", nameA, nameB)
+	renderDiff(o.streams.Out, cs, a, b, !o.noColor && isTerminal(o.streams.Out))
+
+	if o.exitCode {
+		return &ExitError{Code: ExitCodeDiffers, Msg: "secrets differ"}
+	}
+	return nil
+}
+
+// getDecoded fetches the secret for a [namespace/]name reference and returns
+// its decoded data along with its namespace/name
+func (o *EditSecretOptions) getDecoded(ctx context.Context, ref string) (map[string]string, string, error) {
+	namespace, name, key, err := parseSecretRef(ref, o.namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	if key != "" {
+		return nil, "", fmt.Errorf("invalid secret reference %q, expected [namespace/]name", ref)
+	}
+
+	secret, err := o.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+	return decodeData(secret), namespace + "/" + name, nil
+}
+
+// decodeData returns the secret's data as strings
+func decodeData(secret *corev1.Secret) map[string]string {
+	decoded := make(map[string]string, len(secret.Data))
+	for k, v := range secret.Data {
+		decoded[k] = string(v)
+	}
+	return decoded
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestNoSubcommandsShadowSecretNames(t *testing.T) {
+	cmd := NewEditSecretCmd(genericclioptions.NewTestIOStreamsDiscard())
+	for _, name := range []string{"diff", "help", "completion"} {
+		found, _, err := cmd.Find([]string{name})
+		if err != nil || found != cmd {
+			t.Errorf("%q resolves to %v (err %v), want it to be a SECRET_NAME for the root command", name, found.Name(), err)
+		}
+	}
+}
+
+func TestRunDiffWith(t *testing.T) {
+	other := testSecret(map[string]string{"user": "admin", "password": "two", "host": "db"})
+	other.Namespace = "staging"
+	o, _, out, _ := newTestOptions(t, testSecret(map[string]string{"user": "admin", "password": "one"}), other)
+	o.diffWith = "staging/db"
+	o.exitCode = true
+
+	err := o.Run()
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeDiffers {
+		t.Fatalf("Run() error = %v, want exit code %d", err, ExitCodeDiffers)
+	}
+	for _, want := range []string{"--- default/db", "+++ staging/db", "host", "password"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output %q does not contain %q", out.String(), want)
+		}
+	}
+	if strings.Contains(out.String(), "user") {
+		t.Errorf("output %q lists the unchanged key user", out.String())
+	}
+}
+
+func TestRunDiffWithNoDifferences(t *testing.T) {
+	other := testSecret(map[string]string{"password": "one", "rotated-at": "monday"})
+	other.Name = "db-copy"
+	o, _, out, _ := newTestOptions(t, testSecret(map[string]string{"password": "one", "rotated-at": "sunday"}), other)
+	o.diffWith = "db-copy"
+	o.diffIgnore = []string{"rotated-at"}
+	o.exitCode = true
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := "No differences between default/db and default/db-copy.\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// indentDockerConfig pretty-prints the .dockerconfigjson value for editing.
+// A value that is not valid JSON is left as it is.
+func indentDockerConfig(data map[string]string) {
+	v, ok := data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(v), "", "  "); err != nil {
+		return
+	}
+	data[corev1.DockerConfigJsonKey] = buf.String() + "\n"
+}
+
+// checkDockerConfig checks that the edited .dockerconfigjson value is valid
+// JSON
+func checkDockerConfig(edited map[string]string) error {
+	v, ok := edited[corev1.DockerConfigJsonKey]
+	if !ok {
+		return nil
+	}
+	if !json.Valid([]byte(v)) {
+		var probe interface{}
+		return fmt.Errorf("invalid %s: %w", corev1.DockerConfigJsonKey, json.Unmarshal([]byte(v), &probe))
+	}
+	return nil
+}
+
+// compactDockerConfig returns a copy of edited with the .dockerconfigjson
+// value minified again for storing. An unchanged value is left as it is, so
+// it is not rewritten.
+func compactDockerConfig(original, edited map[string]string) (map[string]string, error) {
+	v, ok := edited[corev1.DockerConfigJsonKey]
+	if !ok || v == original[corev1.DockerConfigJsonKey] {
+		return edited, nil
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(v)); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", corev1.DockerConfigJsonKey, err)
+	}
+
+	stored := make(map[string]string, len(edited))
+	for k, val := range edited {
+		stored[k] = val
+	}
+	stored[corev1.DockerConfigJsonKey] = buf.String()
+	return stored, nil
+}
@@ -1,20 +1,50 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"os/exec"
+	"path"
 	"sort"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// Version is the plugin version reported in the User-Agent header.
+// It is overridden by main at startup.
+var Version = "dev"
+
+// Values for --source
+const (
+	sourceAuto       = "auto"
+	sourceData       = "data"
+	sourceStringData = "stringdata"
+)
+
+// Values for --dry-run
+const (
+	dryRunNone   = "none"
+	dryRunClient = "client"
+	dryRunServer = "server"
 )
 
 // EditSecretOptions contains options for the edit-secret command
@@ -22,11 +52,126 @@ type EditSecretOptions struct {
 	configFlags *genericclioptions.ConfigFlags
 	streams     genericclioptions.IOStreams
 
-	namespace  string
-	secretName string
-	key        string
-	editor     string
-	clientset  *kubernetes.Clientset
+	namespace      string
+	secretName     string
+	key            string
+	keys           []string
+	keyPattern     string
+	globKey        bool
+	source         string
+	editor         string
+	editorSource   string
+	printEditor    bool
+	noAutoWait     bool
+	strictEditor   bool
+	userAgent      string
+	stdinSecret    *corev1.Secret
+	clientset      kubernetes.Interface
+	contextName    string
+	server         string
+	requireContext string
+
+	snapshot           bool
+	diffPrevious       bool
+	showPrevious       bool
+	fzf                bool
+	interDelete        bool
+	warnDouble         bool
+	cleanEnv           bool
+	retryEditor        bool
+	reopenOnError      bool
+	previewEnc         bool
+	diffStat           bool
+	diff               bool
+	diffIgnore         []string
+	diffWith           string
+	noColor            bool
+	confirmName        bool
+	confirmApply       bool
+	record             bool
+	emitEvent          bool
+	delimited          bool
+	format             string
+	withMetadata       bool
+	decodeFilter       string
+	encodeFilter       string
+	decodeBase64       bool
+	count              bool
+	get                bool
+	exportEnv          bool
+	force              bool
+	list               bool
+	selector           string
+	allNamespaces      bool
+	fromSecret         string
+	fromServiceAccount string
+	fromURLs           []string
+	sets               []string
+	fromFiles          []string
+	renames            []string
+	deletes            []string
+	allowEmpty         bool
+	importEnvPath      string
+	importMode         string
+	stdin              bool
+	insecureURL        bool
+	onConflict         string
+	retryOnConflict    bool
+	serverSide         bool
+	forceConflicts     bool
+	forceRecreate      bool
+	create             bool
+	secretType         string
+	dryRun             string
+	output             string
+	outputTemplate     string
+	outputChanges      string
+	expectRV           string
+	renderBuffer       bool
+	failOnEmpty        bool
+	warnKeySize        int
+	exportDirPath      string
+	overwrite          bool
+	cloneTo            string
+	backupPath         string
+	timeout            time.Duration
+
+	checkUpdate   bool
+	noUpdateCheck bool
+
+	exitCode        bool
+	quiet           bool
+	verbosity       int
+	successTemplate string
+	successTmpl     *template.Template
+	outputTmpl      *template.Template
+
+	in *bufio.Reader
+
+	// creating is set when --create found no secret to edit, and
+	// overwroteClone when --to replaced an existing secret
+	creating       bool
+	overwroteClone bool
+
+	// base64Keys are the keys shown decoded with --decode-base64-values
+	base64Keys []string
+
+	// metadata and editedMetadata hold the labels and annotations before and
+	// after editing with --with-metadata
+	metadata       *secretMetadata
+	editedMetadata *secretMetadata
+
+	// editorCmd builds the editor process; tests replace it to stand in for
+	// the editor
+	editorCmd func(name string, arg ...string) *exec.Cmd
+	// terminal reports whether a stream is a terminal; tests replace it to
+	// answer prompts from a buffer
+	terminal func(stream interface{}) bool
+
+	mu            sync.Mutex
+	tmpPath       string
+	editorProcess *os.Process
+	cancelled     bool
 }
 
 // NewEditSecretOptions creates new EditSecretOptions with default values
@@ -34,6 +179,8 @@ func NewEditSecretOptions(streams genericclioptions.IOStreams) *EditSecretOption
 	return &EditSecretOptions{
 		configFlags: genericclioptions.NewConfigFlags(true),
 		streams:     streams,
+		editorCmd:   exec.Command,
+		terminal:    isTerminal,
 	}
 }
 
@@ -57,12 +204,74 @@ Examples:
   # Edit a specific key in a secret  
   kubectl edit-secret my-secret password
 
+  # Edit several specific keys
+  kubectl edit-secret db --key user --key password --key host
+
+  # Edit every key matching a glob pattern
+  kubectl edit-secret my-secret 'tls.*' --glob-key
+
+  # List secrets labeled app=db in all namespaces
+  kubectl edit-secret --list -A -l app=db
+
   # Edit a secret in a specific namespace
   kubectl edit-secret my-secret -n my-namespace
 
   # Use a specific editor
-  kubectl edit-secret my-secret --editor=nano`,
-		Args: cobra.RangeArgs(1, 2),
+  kubectl edit-secret my-secret --editor=nano
+
+  # Pick which keys to edit from a fuzzy-searchable list
+  kubectl edit-secret my-secret --fzf
+
+  # Copy a key from a secret in another namespace, then edit
+  kubectl edit-secret my-secret --from-secret=staging/db-credentials:password
+
+  # Write each key to its own file for inspection
+  kubectl edit-secret my-secret --export-dir=./my-secret
+
+  # Set a key from content served by an internal endpoint
+  kubectl edit-secret my-secret --from-url=ca.crt=https://pki.internal/ca.pem
+
+  # Set a value without opening the editor, e.g. in CI
+  kubectl edit-secret my-secret --set password=hunter2
+
+  # Rotate a certificate from local files
+  kubectl edit-secret my-tls --from-file tls.crt=./tls.crt --from-file tls.key=./tls.key
+
+  # Rename a key, keeping its value
+  kubectl edit-secret my-secret --rename DB_PASS=DATABASE_PASSWORD
+
+  # Delete keys without opening the editor
+  kubectl edit-secret my-secret --delete OLD_TOKEN --delete LEGACY_URL
+
+  # Edit a secret referenced by a ServiceAccount
+  kubectl edit-secret --from-serviceaccount=builder
+
+  # Edit a manifest piped on stdin and apply it to the cluster
+  cat secret.yaml | kubectl edit-secret --stdin
+
+  # Read a single value in a script
+  PASSWORD=$(kubectl edit-secret db password --get --force)
+
+  # Print how many keys a secret has
+  kubectl edit-secret my-secret --count
+
+  # Print the updated secret as JSON after applying
+  kubectl edit-secret my-secret -o json
+
+  # Review the resulting secret without applying it
+  kubectl edit-secret my-secret --dry-run=client
+
+  # Edit gzip-compressed values as plain text
+  kubectl edit-secret my-secret --decode-filter="gzip -d" --encode-filter="gzip -n"
+
+  # Compare the decoded values with a secret in another namespace
+  kubectl edit-secret db --diff-with staging/db
+
+  # Record a snapshot on edit, then later see which keys changed since
+  kubectl edit-secret my-secret --snapshot
+  kubectl edit-secret my-secret --diff-previous`,
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: o.completeArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := o.Complete(cmd, args); err != nil {
 				return err
@@ -70,61 +279,227 @@ Examples:
 			if err := o.Validate(); err != nil {
 				return err
 			}
-			return o.Run()
+
+			err := o.timeoutError(o.Run())
+			var exitErr *ExitError
+			if errors.As(err, &exitErr) {
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+			}
+			return err
 		},
 	}
 
-	o.configFlags.AddFlags(cmd.Flags())
+	o.configFlags.AddFlags(cmd.PersistentFlags())
 	cmd.Flags().StringVarP(&o.editor, "editor", "e", "", "Editor to use (defaults to $EDITOR, then vim, then nano)")
+	cmd.Flags().BoolVar(&o.snapshot, "snapshot", false, "Record hashes of the pre-edit values in an annotation")
+	cmd.Flags().BoolVar(&o.diffPrevious, "diff-previous", false, "Show which keys changed since the last --snapshot, without editing")
+	cmd.Flags().BoolVar(&o.showPrevious, "show-previous", false, "Show how the values differ from the last-applied configuration, without editing")
+	cmd.Flags().BoolVar(&o.noAutoWait, "no-auto-wait", false, "Do not add the wait flag (e.g. code --wait) to known GUI editors")
+	cmd.Flags().BoolVar(&o.printEditor, "print-editor", false, "Print the resolved editor and where it came from")
+	cmd.Flags().StringArrayVar(&o.keys, "key", nil, "Edit only this key, repeatable to edit several keys (instead of KEY)")
+	cmd.Flags().BoolVar(&o.globKey, "glob-key", false, "Treat KEY as a glob pattern and edit every matching key")
+	cmd.Flags().StringVar(&o.source, "source", sourceAuto, "Where to read KEY from: data, stringdata, or auto (stringData first, then data)")
+	cmd.Flags().BoolVar(&o.fzf, "fzf", false, "Interactively pick the keys to edit when KEY is omitted")
+	cmd.Flags().BoolVar(&o.interDelete, "interactive-delete", false, "Pick keys to delete from a list and apply, without opening the editor")
+	cmd.Flags().IntVar(&o.warnKeySize, "warn-key-size", 0, "Warn before editing about keys whose decoded value exceeds this many bytes (0 disables)")
+	cmd.Flags().BoolVar(&o.warnDouble, "warn-double-encode", false, "Ask for confirmation when an edited value looks already base64-encoded")
+	cmd.Flags().BoolVarP(&o.quiet, "quiet", "q", false, "Only print errors and requested output, not status messages such as the success line")
+	cmd.Flags().IntVarP(&o.verbosity, "v", "v", 0, "Log level: 1 context, server, and namespace; 3 loaded and changed keys; 6 each API request with its timing (capped at 7, never logs values)")
+	cmd.Flags().BoolVar(&o.exitCode, "exit-code", false, "Exit with code 3 if the file was not edited and 4 if the edit changed no values; with --diff-with, 1 if the secrets differ")
+	cmd.Flags().StringVar(&o.successTemplate, "success-template", "", "Go template for the success message, e.g. \"edited {{.Name}} ({{.ChangedCount}} keys)\"")
+	cmd.Flags().BoolVar(&o.strictEditor, "strict-editor", false, "Fail when the editor exits with a non-zero status instead of treating it as an aborted edit")
+	cmd.Flags().BoolVar(&o.cleanEnv, "editor-clean-env", false, "Run the editor with a minimal environment (HOME, USER, TERM, PATH, LANG, TMPDIR and Windows essentials)")
+	cmd.Flags().BoolVar(&o.retryEditor, "retry-editor", false, "After the editor closes, offer to reopen it on the same buffer before applying")
+	cmd.Flags().BoolVar(&o.reopenOnError, "reopen-on-error", true, "When the edited buffer is invalid, offer to reopen the editor on it to fix the mistake")
+	cmd.Flags().BoolVar(&o.previewEnc, "preview-encoded", false, "Print the base64 value that will be stored for each changed key before applying")
+	cmd.Flags().BoolVar(&o.diffStat, "diff-stat", false, "Print how many keys and lines changed before applying, without values")
+	cmd.Flags().BoolVar(&o.diff, "diff", false, "Print a line diff of the changed values before applying")
+	cmd.Flags().BoolVar(&o.noColor, "no-color", false, "Do not colorize --diff output (color is only used on a terminal)")
+	cmd.Flags().StringSliceVar(&o.diffIgnore, "diff-ignore", nil, "Keys to leave out of --diff, --diff-stat, and --diff-with, comma-separated (they are still applied)")
+	cmd.Flags().StringVar(&o.diffWith, "diff-with", "", "Compare the decoded values with another secret ([namespace/]name) and exit, without editing")
+	cmd.Flags().BoolVar(&o.confirmApply, "confirm", false, "Show how many keys will be added, changed, and removed and ask before applying")
+	cmd.Flags().BoolVar(&o.confirmName, "confirm-word", false, "Require typing the secret name before applying")
+	cmd.Flags().BoolVar(&o.record, "record", false, "Update the last-applied-configuration annotation like kubectl apply")
+	cmd.Flags().BoolVar(&o.emitEvent, "emit-event", false, "Create an Event on the secret listing the changed keys (never values)")
+	cmd.Flags().StringVar(&o.decodeFilter, "decode-filter", "", "Command each stored value is piped through before editing (requires --encode-filter)")
+	cmd.Flags().StringVar(&o.encodeFilter, "encode-filter", "", "Command each changed value is piped through before storing (requires --decode-filter)")
+	cmd.Flags().BoolVar(&o.decodeBase64, "decode-base64-values", false, "Show values that are themselves base64-encoded decoded, and re-encode them on save")
+	cmd.Flags().StringVar(&o.format, "format", formatYAML, "Format of the edit buffer: yaml or json")
+	cmd.Flags().BoolVar(&o.delimited, "delimited", false, "Wrap each value in BEGIN/END marker lines instead of YAML")
+	cmd.Flags().BoolVar(&o.renderBuffer, "render-buffer", false, "Print the edit buffer to stdout and exit, without launching the editor")
+	cmd.Flags().BoolVar(&o.failOnEmpty, "fail-on-empty", false, "Exit with code 5 if the secret is missing and 6 if it has no data")
+	cmd.Flags().BoolVar(&o.count, "count", false, "Print the number of keys in the secret and exit, without editing")
+	cmd.Flags().BoolVar(&o.list, "list", false, "List secrets and their key counts instead of editing")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Label selector to filter --list, e.g. app=db")
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "With --list, list secrets across all namespaces")
+	cmd.Flags().BoolVar(&o.get, "get", false, "Print the decoded value of KEY exactly as stored, without a trailing newline, and exit")
+	cmd.Flags().StringVar(&o.fromServiceAccount, "from-serviceaccount", "", "Edit a secret referenced by this ServiceAccount instead of SECRET_NAME")
+	cmd.Flags().BoolVar(&o.stdin, "stdin", false, "Read a full Secret manifest (YAML or JSON) from stdin, edit it, and apply it")
+	cmd.Flags().StringVar(&o.fromSecret, "from-secret", "", "Copy keys from another secret ([namespace/]name[:key]) into the edit")
+	cmd.Flags().StringArrayVar(&o.fromURLs, "from-url", nil, "Set a key from content fetched over HTTPS (key=https://...), repeatable")
+	cmd.Flags().StringArrayVar(&o.sets, "set", nil, "Set a key to a value (key=value) and apply without opening the editor, repeatable")
+	cmd.Flags().StringArrayVar(&o.fromFiles, "from-file", nil, "Set a key to the exact contents of a local file (key=path) and apply without opening the editor, repeatable")
+	cmd.Flags().StringArrayVar(&o.renames, "rename", nil, "Rename a key, keeping its value (old=new), and apply without opening the editor, repeatable")
+	cmd.Flags().StringArrayVar(&o.deletes, "delete", nil, "Delete a key and apply without opening the editor, repeatable")
+	cmd.Flags().BoolVar(&o.allowEmpty, "allow-empty", false, "Allow --delete or --import-mode=replace to remove the last keys of the secret")
+	cmd.Flags().StringVar(&o.importEnvPath, "import-env", "", "Set the keys of a .env file and apply without opening the editor")
+	cmd.Flags().StringVar(&o.importMode, "import-mode", importMerge, "How --import-env treats keys missing from the file: merge keeps them, replace removes them")
+	cmd.Flags().BoolVar(&o.insecureURL, "insecure-url", false, "Allow plain http:// URLs, and redirects to them, for --from-url")
+	cmd.Flags().BoolVar(&o.overwrite, "overwrite", false, "Allow copied or renamed keys to replace existing keys, and --to to replace an existing secret")
+	cmd.Flags().StringVar(&o.cloneTo, "to", "", "Write the edited result to a new secret with this name instead of updating the source")
+	cmd.Flags().StringVar(&o.exportDirPath, "export-dir", "", "Write each decoded key to its own file (mode 0600) in this directory and exit")
+	cmd.Flags().BoolVar(&o.force, "force", false, "Allow --get, --export-env, --render-buffer, --show-previous, --diff, --diff-with, --template, and --preview-encoded to print decoded values when the output is not a terminal")
+	cmd.Flags().BoolVar(&o.exportEnv, "export-env", false, "Print the decoded keys as KEY=value lines for a .env file and exit")
+	cmd.Flags().StringVar(&o.backupPath, "backup", "", "Before applying, save the unedited secret as a manifest to this file, or to a timestamped file in this directory")
+	cmd.Flags().BoolVar(&o.withMetadata, "with-metadata", false, "Also edit the secret's labels and annotations in the buffer")
+	cmd.Flags().BoolVar(&o.create, "create", false, "Create the secret if it does not exist, starting from an empty buffer")
+	cmd.Flags().StringVar(&o.secretType, "type", "", "Type of the secret created with --create (default Opaque) or --to (default the source type)")
+	cmd.Flags().StringVar(&o.dryRun, "dry-run", dryRunNone, "Do not persist changes: \"client\" prints the resulting secret, \"server\" submits it for validation only")
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = dryRunClient
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Print the resulting secret as yaml, json, or go-template instead of the success message")
+	cmd.Flags().StringVar(&o.outputChanges, "output-changes", "", "Print the added, changed, and removed key names as JSON instead of the success message (only \"json\" is supported)")
+	cmd.Flags().StringVar(&o.outputTemplate, "template", "", "Go template printed over the resulting secret, implies -o go-template, e.g. '{{ index .data \"password\" | b64dec }}'")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", defaultTimeout, "How long to wait for the API server when reading or writing the secret (0 means no limit); time in the editor is not counted")
+	cmd.Flags().StringVar(&o.onConflict, "on-conflict", conflictMerge, "What to do if the secret changed while editing: abort, overwrite, or merge")
+	cmd.Flags().BoolVar(&o.retryOnConflict, "retry-on-conflict", false, "On conflict, re-apply your changes to the latest version and retry with backoff, aborting if a key you changed was also changed on the server")
+	cmd.Flags().BoolVar(&o.serverSide, "server-side", false, "Write only the changed keys with a server-side apply (field manager "+fieldManager+") instead of a full update")
+	cmd.Flags().BoolVar(&o.forceConflicts, "force-conflicts", false, "With --server-side, take over keys owned by another field manager instead of failing")
+	cmd.Flags().BoolVar(&o.forceRecreate, "force-recreate", false, "Replace the secret by deleting and recreating it, e.g. to change an immutable secret (asks to type the name)")
+	cmd.Flags().StringVar(&o.expectRV, "expect-resource-version", "", "Only apply if the secret is still at this resourceVersion")
+	cmd.Flags().BoolVar(&o.checkUpdate, "check-update", false, "Check GitHub for a newer release and print a notice")
+	cmd.Flags().BoolVar(&o.noUpdateCheck, "no-update-check", false, "Never check for updates, even with --check-update (also $"+noUpdateCheckEnv+")")
+	cmd.Flags().StringVar(&o.requireContext, "require-context", "", "Refuse to run unless the current kubeconfig context is this one")
+	cmd.Flags().StringVar(&o.userAgent, "user-agent", "", "User-Agent sent to the API server (defaults to kubectl-edit-secret/<version>)")
 
 	return cmd
 }
 
 // Complete fills in fields required to run
 func (o *EditSecretOptions) Complete(cmd *cobra.Command, args []string) error {
-	o.secretName = args[0]
+	if err := o.setupLogging(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.secretName = args[0]
+	}
 	if len(args) > 1 {
 		o.key = args[1]
 	}
 
-	var err error
-	o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if o.globKey && strings.ContainsAny(o.key, "*?[") {
+		o.keyPattern, o.key = o.key, ""
+	}
+
+	var (
+		err               error
+		explicitNamespace bool
+	)
+	o.namespace, explicitNamespace, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
 	if err != nil {
 		return fmt.Errorf("failed to get namespace: %w", err)
 	}
+	o.defaultNamespace()
 
-	restConfig, err := o.configFlags.ToRESTConfig()
-	if err != nil {
-		return fmt.Errorf("failed to create REST config: %w", err)
+	if o.stdin {
+		if err := o.readStdinSecret(explicitNamespace); err != nil {
+			return err
+		}
+	}
+
+	if err := o.resolveContext(); err != nil {
+		return err
 	}
+	klog.V(1).Infof("Using context %q, server %s, namespace %q", o.contextName, o.server, o.namespace)
 
-	o.clientset, err = kubernetes.NewForConfig(restConfig)
+	o.clientset, err = newClientset(o.configFlags, o.userAgent, o.streams)
 	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+		return err
 	}
 
+	if !o.needsEditor() {
+		return nil
+	}
 	return o.resolveEditor()
 }
 
+// needsEditor reports whether the selected mode opens an editor
+func (o *EditSecretOptions) needsEditor() bool {
+	return o.diffWith == "" && !o.diffPrevious && !o.showPrevious && !o.count && !o.get && !o.list && o.exportDirPath == "" && !o.exportEnv && !o.renderBuffer && !o.interDelete && !o.setsValues()
+}
+
+// newClientset creates a Kubernetes client from the config flags. An empty
+// userAgent defaults to kubectl-edit-secret/<version>.
+func newClientset(configFlags *genericclioptions.ConfigFlags, userAgent string, streams genericclioptions.IOStreams) (kubernetes.Interface, error) {
+	restConfig, err := newRESTConfig(configFlags, userAgent, streams)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	return clientset, nil
+}
+
+// newRESTConfig creates the REST config for newClientset, with the user agent
+// set and API warnings written to the error stream
+func newRESTConfig(configFlags *genericclioptions.ConfigFlags, userAgent string, streams genericclioptions.IOStreams) (*rest.Config, error) {
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST config: %w", err)
+	}
+
+	if userAgent == "" {
+		userAgent = fmt.Sprintf("kubectl-edit-secret/%s", Version)
+	}
+	restConfig.UserAgent = userAgent
+
+	// Print API warnings (deprecations, policy notices) instead of dropping them
+	restConfig.WarningHandler = rest.NewWarningWriter(streams.ErrOut, rest.WarningWriterOptions{Deduplicate: true})
+	return restConfig, nil
+}
+
 // resolveEditor determines which editor to use
 func (o *EditSecretOptions) resolveEditor() error {
+	if err := o.lookupEditor(); err != nil {
+		return err
+	}
+	if _, _, err := parseEditor(o.editor); err != nil {
+		return fmt.Errorf("editor from %s: %w", o.editorSource, err)
+	}
+
+	if o.printEditor {
+		fmt.Fprintf(o.streams.ErrOut, "Using editor %q (from %s)\n", o.editor, o.editorSource)
+	}
+	return nil
+}
+
+// lookupEditor finds the editor and records where it came from
+func (o *EditSecretOptions) lookupEditor() error {
 	if o.editor != "" {
+		o.editorSource = "--editor flag"
 		return nil
 	}
 
 	if editor := os.Getenv("KUBE_EDITOR"); editor != "" {
 		o.editor = editor
+		o.editorSource = "$KUBE_EDITOR"
 		return nil
 	}
 
 	if editor := os.Getenv("EDITOR"); editor != "" {
 		o.editor = editor
+		o.editorSource = "$EDITOR"
 		return nil
 	}
 
 	for _, e := range []string{"vim", "vi", "nano", "notepad"} {
 		if _, err := exec.LookPath(e); err == nil {
 			o.editor = e
+			o.editorSource = "fallback:" + e
 			return nil
 		}
 	}
@@ -134,46 +509,641 @@ func (o *EditSecretOptions) resolveEditor() error {
 
 // Validate ensures options are valid
 func (o *EditSecretOptions) Validate() error {
-	if o.secretName == "" {
+	if o.stdin && o.fromServiceAccount != "" {
+		return fmt.Errorf("--stdin cannot be combined with --from-serviceaccount")
+	}
+
+	if o.list {
+		if o.secretName != "" {
+			return fmt.Errorf("--list cannot be combined with SECRET_NAME")
+		}
+	} else if o.selector != "" || o.allNamespaces {
+		return fmt.Errorf("--selector and --all-namespaces can only be used with --list")
+	} else if o.fromServiceAccount != "" {
+		if o.secretName != "" {
+			return fmt.Errorf("--from-serviceaccount cannot be combined with SECRET_NAME")
+		}
+	} else if o.secretName == "" {
 		return fmt.Errorf("secret name is required")
 	}
+
+	switch o.source {
+	case sourceAuto, sourceData, sourceStringData:
+	default:
+		return fmt.Errorf("invalid --source %q, must be one of: auto, data, stringdata", o.source)
+	}
+
+	if (o.decodeFilter == "") != (o.encodeFilter == "") {
+		return fmt.Errorf("--decode-filter and --encode-filter must be used together")
+	}
+
+	switch o.format {
+	case formatYAML, formatJSON:
+	default:
+		return fmt.Errorf("invalid --format %q, must be one of: yaml, json", o.format)
+	}
+	if o.format == formatJSON && o.delimited {
+		return fmt.Errorf("--format=json cannot be combined with --delimited")
+	}
+	if o.withMetadata {
+		if o.format == formatJSON || o.delimited {
+			return fmt.Errorf("--with-metadata cannot be combined with --format=json or --delimited")
+		}
+		if o.serverSide {
+			return fmt.Errorf("--with-metadata cannot be combined with --server-side")
+		}
+		if o.setsValues() {
+			return fmt.Errorf("--with-metadata requires the editor and cannot be combined with --set, --from-file, --rename, --delete, or --import-env")
+		}
+	}
+
+	switch o.dryRun {
+	case dryRunNone, dryRunClient, dryRunServer:
+	default:
+		return fmt.Errorf("invalid --dry-run %q, must be one of: none, client, server", o.dryRun)
+	}
+
+	if o.outputTemplate != "" && o.output == "" {
+		o.output = outputGoTemplate
+	}
+	switch o.output {
+	case "", outputYAML, outputJSON:
+		if o.outputTemplate != "" {
+			return fmt.Errorf("--template cannot be combined with --output=%s", o.output)
+		}
+	case outputGoTemplate:
+		if o.outputTemplate == "" {
+			return fmt.Errorf("--output=go-template requires --template")
+		}
+		tmpl, err := parseOutputTemplate(o.outputTemplate)
+		if err != nil {
+			return fmt.Errorf("invalid --template: %w", err)
+		}
+		o.outputTmpl = tmpl
+	default:
+		return fmt.Errorf("invalid --output %q, must be one of: yaml, json, go-template", o.output)
+	}
+
+	switch o.outputChanges {
+	case "":
+	case outputChangesJSON:
+		if o.output != "" || o.successTemplate != "" {
+			return fmt.Errorf("--output-changes cannot be combined with --output, --template, or --success-template")
+		}
+	default:
+		return fmt.Errorf("invalid --output-changes %q, must be: json", o.outputChanges)
+	}
+
+	if o.timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative")
+	}
+
+	switch o.onConflict {
+	case conflictAbort, conflictOverwrite, conflictMerge:
+	default:
+		return fmt.Errorf("invalid --on-conflict %q, must be one of: abort, overwrite, merge", o.onConflict)
+	}
+	if o.retryOnConflict && o.onConflict != conflictMerge {
+		return fmt.Errorf("--retry-on-conflict cannot be combined with --on-conflict=%s", o.onConflict)
+	}
+	if o.forceRecreate && (o.serverSide || o.dryRun == dryRunServer) {
+		return fmt.Errorf("--force-recreate cannot be combined with --server-side or --dry-run=server")
+	}
+	if o.create {
+		if o.key != "" || o.keyPattern != "" || len(o.keys) > 0 {
+			return fmt.Errorf("--create cannot be combined with KEY or --key")
+		}
+		if o.stdin || o.fromServiceAccount != "" || o.list {
+			return fmt.Errorf("--create cannot be combined with --stdin, --from-serviceaccount, or --list")
+		}
+		if o.forceRecreate || o.expectRV != "" {
+			return fmt.Errorf("--create cannot be combined with --force-recreate or --expect-resource-version")
+		}
+	} else if o.secretType != "" && o.cloneTo == "" {
+		return fmt.Errorf("--type can only be used with --create or --to")
+	}
+	if o.forceConflicts && !o.serverSide {
+		return fmt.Errorf("--force-conflicts can only be used with --server-side")
+	}
+	if o.serverSide && o.retryOnConflict {
+		return fmt.Errorf("--retry-on-conflict cannot be combined with --server-side, which does not conflict on concurrent changes")
+	}
+
+	if len(o.keys) > 0 && (o.key != "" || o.keyPattern != "") {
+		return fmt.Errorf("--key cannot be combined with a KEY argument")
+	}
+
+	if o.get && o.key == "" {
+		return fmt.Errorf("--get requires a KEY argument naming a single key")
+	}
+
+	if o.interDelete {
+		if o.key != "" || o.keyPattern != "" || len(o.keys) > 0 {
+			return fmt.Errorf("--interactive-delete cannot be combined with KEY or --key")
+		}
+		if !o.terminal(o.streams.In) {
+			return fmt.Errorf("--interactive-delete requires an interactive terminal")
+		}
+	}
+
+	if o.fromSecret != "" && (o.key != "" || len(o.keys) > 0) {
+		return fmt.Errorf("--from-secret cannot be combined with KEY or --key")
+	}
+
+	if len(o.fromURLs) > 0 && (o.key != "" || len(o.keys) > 0) {
+		return fmt.Errorf("--from-url cannot be combined with KEY or --key")
+	}
+	if err := o.validateFromURLs(); err != nil {
+		return err
+	}
+	if err := o.validateSets(); err != nil {
+		return err
+	}
+	if err := o.validateClone(); err != nil {
+		return err
+	}
+	if err := o.validateDiffWith(); err != nil {
+		return err
+	}
+	if err := o.checkPlaintextOutput(); err != nil {
+		return err
+	}
+
+	if o.successTemplate != "" {
+		tmpl, err := template.New("success").Parse(o.successTemplate)
+		if err != nil {
+			return fmt.Errorf("invalid --success-template: %w", err)
+		}
+		o.successTmpl = tmpl
+	}
 	return nil
 }
 
 // Run executes the edit-secret command
-func (o *EditSecretOptions) Run() error {
-	ctx := context.Background()
+func (o *EditSecretOptions) Run() (err error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopSignals := o.handleSignals(cancel)
+	defer func() {
+		stopSignals()
+		// A change that was written before the signal arrived still stands
+		if err != nil && o.wasCancelled() {
+			err = o.cancelledError()
+		}
+	}()
 
-	secret, err := o.clientset.CoreV1().Secrets(o.namespace).Get(ctx, o.secretName, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get secret %s: %w", o.secretName, err)
+	if o.updateCheckEnabled() {
+		defer o.checkForUpdate()
+	}
+
+	// Requests made before the editor opens share one timeout, started again
+	// after each prompt so the time spent answering is not counted; applying
+	// the edit gets its own in applyChanges
+	loadCtx, cancelLoad := o.withTimeout(ctx)
+	defer func() { cancelLoad() }()
+	restartLoad := func() {
+		cancelLoad()
+		loadCtx, cancelLoad = o.withTimeout(ctx)
+	}
+
+	if o.list {
+		return o.listSecrets(loadCtx)
+	}
+
+	if o.diffWith != "" {
+		return o.runDiffWith(loadCtx)
+	}
+
+	if o.fromServiceAccount != "" {
+		if err := o.resolveServiceAccountSecret(loadCtx); err != nil {
+			return err
+		}
+		restartLoad()
+	}
+
+	secret := o.stdinSecret
+	if secret != nil {
+		if err := o.reconcileStdinSecret(loadCtx); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		secret, err = o.clientset.CoreV1().Secrets(o.namespace).Get(loadCtx, o.secretName, metav1.GetOptions{})
+		if o.create && apierrors.IsNotFound(err) {
+			secret, err = o.newSecret(), nil
+			o.creating = true
+			fmt.Fprintf(o.streams.ErrOut, "Secret %s does not exist in namespace %s, it will be created.\n", o.secretName, o.namespace)
+		}
+		if o.failOnEmpty && apierrors.IsNotFound(err) {
+			return o.reportExit(ExitCodeNotFound, fmt.Sprintf("secret %s not found in namespace %s", o.secretName, o.namespace))
+		}
+		if apierrors.IsNotFound(err) {
+			if hint := o.suggestSecretNames(loadCtx); hint != "" {
+				return fmt.Errorf("failed to get secret %s: %w. %s", o.secretName, err, hint)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get secret %s: %w", o.secretName, o.forbiddenError("get", err))
+		}
+	}
+
+	if !o.creating {
+		klog.V(3).Infof("Loaded secret %s/%s at resourceVersion %s with %d keys", secret.Namespace, secret.Name, secret.ResourceVersion, len(secret.Data))
+	}
+
+	if err := o.checkCloneTarget(loadCtx); err != nil {
+		return err
+	}
+
+	if o.failOnEmpty && len(secret.Data) == 0 && !o.creating {
+		return o.reportExit(ExitCodeEmpty, fmt.Sprintf("secret %s exists but has no data", o.secretName))
+	}
+
+	if o.fromServiceAccount != "" && secret.Type == corev1.SecretTypeServiceAccountToken {
+		fmt.Fprintf(o.streams.ErrOut, "Warning: secret %s is a service account token managed by the token controller; edits are usually overwritten\n", o.secretName)
+	}
+
+	if o.expectRV != "" && secret.ResourceVersion != o.expectRV {
+		return o.resourceVersionMismatch(secret.ResourceVersion)
+	}
+
+	if o.diffPrevious {
+		return o.printSnapshotDiff(secret)
+	}
+
+	if o.count {
+		fmt.Fprintln(o.streams.Out, len(secret.Data))
+		return nil
+	}
+
+	if o.exportDirPath != "" {
+		return o.exportDir(secret)
 	}
 
 	decodedData, err := o.extractDecodedData(secret)
 	if err != nil {
 		return err
 	}
+	if o.fzf {
+		restartLoad()
+	}
 
-	editedData, err := o.editInEditor(decodedData)
-	if err != nil {
+	if o.decodeFilter != "" {
+		if err := o.decodeFilterValues(decodedData); err != nil {
+			return err
+		}
+	}
+
+	if o.decodeBase64 {
+		o.base64Keys = decodeBase64Values(decodedData)
+	}
+
+	if o.get {
+		_, err := io.WriteString(o.streams.Out, decodedData[o.key])
 		return err
 	}
 
+	if o.exportEnv {
+		return o.writeEnv(decodedData)
+	}
+
+	if o.showPrevious {
+		return o.printPrevious(secret, decodedData)
+	}
+
+	if secret.Immutable != nil && *secret.Immutable && !o.renderBuffer && !o.forceRecreate && o.cloneTo == "" {
+		return fmt.Errorf("secret %s is immutable, so its data cannot be changed; it must be deleted and recreated with the new values (use --force-recreate)", o.secretName)
+	}
+
+	if secret.Type == corev1.SecretTypeDockerConfigJson {
+		indentDockerConfig(decodedData)
+	}
+
+	if o.interDelete {
+		return o.runInteractiveDelete(ctx, secret, decodedData)
+	}
+
+	buffer := decodedData
+	if o.seeded() {
+		buffer = make(map[string]string, len(decodedData))
+		for k, v := range decodedData {
+			buffer[k] = v
+		}
+		if err := o.seedBuffer(loadCtx, secret, buffer); err != nil {
+			return err
+		}
+	}
+
+	if o.warnKeySize > 0 {
+		o.warnOversizedKeys(buffer)
+	}
+
+	if o.withMetadata {
+		o.metadata = editableMetadata(secret)
+	}
+
+	if o.renderBuffer {
+		content, err := o.createEditContent(buffer)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(o.streams.Out, content)
+		return nil
+	}
+
+	// --set, --from-file, --rename, --delete, and --import-env apply the
+	// seeded buffer directly, without the editor
+	check := editCheck(secret)
+	var editedData map[string]string
+	if o.setsValues() {
+		if err := check(buffer); err != nil {
+			return err
+		}
+	}
+	if !o.setsValues() {
+		editedData, err = o.editInEditor(buffer, check)
+		if errors.Is(err, errEditAborted) {
+			o.info("Aborted.\n")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Copied values, and a --to clone, are applied even if they are not
+	// edited further
+	if editedData == nil && (o.seeded() || o.cloneTo != "") {
+		editedData = buffer
+	}
+
 	if editedData == nil {
-		fmt.Fprintln(o.streams.Out, "Edit cancelled, no changes made.")
+		o.info("Edit cancelled, the file was not modified.\n")
+		return o.exitWith(ExitCodeNotEdited, "file not modified")
+	}
+
+	if !o.hasChanges(decodedData, editedData) && !o.metadataChanged() && o.cloneTo == "" {
+		o.info("No changes detected, the edited values match the secret.\n")
+		return o.exitWith(ExitCodeUnchanged, "no changes detected")
+	}
+
+	if o.warnDouble {
+		proceed, err := o.confirmDoubleEncoded(decodedData, editedData)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			o.info("Aborted.\n")
+			return nil
+		}
+	}
+
+	// original and stored are the values before and after the edit in the
+	// form they are stored in
+	original, stored := decodedData, editedData
+	if secret.Type == corev1.SecretTypeDockerConfigJson {
+		if stored, err = compactDockerConfig(decodedData, stored); err != nil {
+			return err
+		}
+	}
+	if len(o.base64Keys) > 0 {
+		original = encodeBase64Values(original, o.base64Keys)
+		stored = encodeBase64Values(stored, o.base64Keys)
+	}
+	if o.encodeFilter != "" {
+		if stored, err = o.encodeFilterValues(original, stored); err != nil {
+			return err
+		}
+	}
+
+	if o.previewEnc {
+		o.printEncodedPreview(original, stored)
+	}
+
+	if o.diff {
+		cs := o.changes(decodedData, editedData).without(o.diffIgnore)
+		renderDiff(o.streams.Out, cs, decodedData, editedData, !o.noColor && isTerminal(o.streams.Out))
+	}
+
+	if o.diffStat {
+		cs := o.changes(decodedData, editedData).without(o.diffIgnore)
+		fmt.Fprintln(o.streams.Out, computeDiffStat(cs, decodedData, editedData))
+	}
+
+	if o.confirmApply {
+		cs := o.changes(decodedData, editedData)
+		proceed, err := o.confirm(fmt.Sprintf("Apply %d added, %d changed, and %d removed key(s) to secret %s?", len(cs.Added), len(cs.Changed), len(cs.Removed), o.secretName))
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			o.info("Aborted.\n")
+			return nil
+		}
+	}
+
+	if o.confirmName {
+		proceed, err := o.confirmWord("apply changes to secret "+o.secretName, o.secretName)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			o.info("Aborted.\n")
+			return nil
+		}
+	}
+
+	updated, err := o.applyChanges(ctx, secret, original, stored)
+	if err != nil {
+		return err
+	}
+
+	cs := o.changes(decodedData, editedData)
+	if o.emitEvent && o.dryRun == dryRunNone {
+		o.emitEditEvent(ctx, updated, cs)
+	}
+	return o.printResult(updated, cs)
+}
+
+// runInteractiveDelete lets the user pick keys to delete, confirms, and applies
+func (o *EditSecretOptions) runInteractiveDelete(ctx context.Context, secret *corev1.Secret, decodedData map[string]string) error {
+	keys := make([]string, 0, len(decodedData))
+	for k := range decodedData {
+		keys = append(keys, k)
+	}
+
+	selected, err := o.selectKeys("Select keys to delete", keys)
+	if err != nil {
+		return err
+	}
+
+	edited := make(map[string]string, len(decodedData))
+	for k, v := range decodedData {
+		edited[k] = v
+	}
+	for _, k := range selected {
+		delete(edited, k)
+	}
+
+	proceed, err := o.confirm(fmt.Sprintf("Delete %d key(s) (%s) from secret %s?", len(selected), strings.Join(selected, ", "), o.secretName))
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		o.info("Aborted.\n")
+		return nil
+	}
+
+	updated, err := o.applyChanges(ctx, secret, decodedData, edited)
+	if err != nil {
+		return err
+	}
+
+	cs := o.changes(decodedData, edited)
+	if o.emitEvent && o.dryRun == dryRunNone {
+		o.emitEditEvent(ctx, updated, cs)
+	}
+	return o.printResult(updated, cs)
+}
+
+// dryRunSuffix returns the marker appended to messages in dry-run mode,
+// following kubectl's convention
+func (o *EditSecretOptions) dryRunSuffix() string {
+	switch o.dryRun {
+	case dryRunClient:
+		return " (dry run, no changes applied)"
+	case dryRunServer:
+		return " (server dry run, no changes applied)"
+	}
+	return ""
+}
+
+// successInfo is the data available to --success-template. It never
+// carries secret values.
+type successInfo struct {
+	Name         string
+	Namespace    string
+	ChangedCount int
+	AddedCount   int
+	RemovedCount int
+}
+
+// printResult reports the applied secret: as a manifest with --output, as the
+// changed key names with --output-changes, otherwise as the success line,
+// preceded by the manifest for --dry-run=client
+func (o *EditSecretOptions) printResult(secret *corev1.Secret, cs changeSet) error {
+	if o.outputTmpl != nil {
+		return printSecretTemplate(o.streams.Out, secret, o.outputTmpl)
+	}
+	if o.output != "" {
+		return printSecret(o.streams.Out, secret, o.output)
+	}
+	if o.outputChanges != "" {
+		return printChanges(o.streams.Out, cs)
+	}
+	if o.dryRun == dryRunClient {
+		if err := printSecret(o.streams.Out, secret, outputYAML); err != nil {
+			return err
+		}
+	}
+	return o.printSuccess(cs)
+}
+
+// info prints an informational message to stdout unless --quiet is set
+func (o *EditSecretOptions) info(format string, args ...interface{}) {
+	if !o.quiet {
+		fmt.Fprintf(o.streams.Out, format, args...)
+	}
+}
+
+// printSuccess prints the success message, using --success-template if set
+func (o *EditSecretOptions) printSuccess(cs changeSet) error {
+	if o.quiet {
+		return nil
+	}
+	if o.successTmpl == nil {
+		verb := "edited"
+		if o.creating {
+			verb = "created"
+		}
+		if o.cloneTo != "" {
+			verb = "created from secret/" + o.secretName
+			if o.overwroteClone {
+				verb = "replaced from secret/" + o.secretName
+			}
+		}
+		fmt.Fprintf(o.streams.Out, "secret/%s %s%s\n", o.resultName(), verb, o.dryRunSuffix())
 		return nil
 	}
 
-	if !o.hasChanges(decodedData, editedData) {
-		fmt.Fprintln(o.streams.Out, "No changes detected.")
+	var buf bytes.Buffer
+	err := o.successTmpl.Execute(&buf, successInfo{
+		Name:         o.resultName(),
+		Namespace:    o.namespace,
+		ChangedCount: len(cs.Changed),
+		AddedCount:   len(cs.Added),
+		RemovedCount: len(cs.Removed),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render --success-template: %w", err)
+	}
+
+	fmt.Fprintln(o.streams.Out, strings.TrimRight(buf.String(), "\n"))
+	return nil
+}
+
+// resultName returns the name of the secret that was written
+func (o *EditSecretOptions) resultName() string {
+	if o.cloneTo != "" {
+		return o.cloneTo
+	}
+	return o.secretName
+}
+
+// exitWith returns an ExitError with the given code when --exit-code is set,
+// and nil otherwise
+func (o *EditSecretOptions) exitWith(code int, msg string) error {
+	if !o.exitCode {
 		return nil
 	}
+	return &ExitError{Code: code, Msg: msg}
+}
+
+// reportExit prints msg as an error and returns an ExitError with the given code
+func (o *EditSecretOptions) reportExit(code int, msg string) error {
+	fmt.Fprintf(o.streams.ErrOut, "Error: %s\n", msg)
+	return &ExitError{Code: code, Msg: msg}
+}
+
+// seeded reports whether values from other sources are added to the buffer
+func (o *EditSecretOptions) seeded() bool {
+	return o.fromSecret != "" || len(o.fromURLs) > 0 || o.setsValues()
+}
+
+// seedBuffer adds the values from --from-secret, --from-url, --rename,
+// --delete, --import-env, --from-file, and --set to the buffer
+func (o *EditSecretOptions) seedBuffer(ctx context.Context, secret *corev1.Secret, buffer map[string]string) error {
+	if o.fromSecret != "" {
+		if err := o.seedFromSecret(ctx, secret, buffer); err != nil {
+			return err
+		}
+	}
+	if err := o.seedFromURLs(ctx, buffer); err != nil {
+		return err
+	}
+	return o.seedFromSets(buffer)
+}
 
-	if err := o.applyChanges(ctx, secret, decodedData, editedData); err != nil {
+// printSnapshotDiff prints which keys changed since the last snapshot
+func (o *EditSecretOptions) printSnapshotDiff(secret *corev1.Secret) error {
+	lines, err := diffSnapshot(secret)
+	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(o.streams.Out, "secret/%s edited\n", o.secretName)
+	for _, line := range lines {
+		fmt.Fprintln(o.streams.Out, line)
+	}
 	return nil
 }
 
@@ -185,89 +1155,334 @@ func (o *EditSecretOptions) extractDecodedData(secret *corev1.Secret) (map[strin
 		return o.extractSingleKey(secret, decodedData)
 	}
 
-	for k, v := range secret.Data {
-		decodedData[k] = string(v)
+	if len(o.keys) > 0 {
+		return o.extractKeys(secret, decodedData)
 	}
 
-	if len(decodedData) == 0 {
+	if o.keyPattern != "" {
+		return o.extractMatchingKeys(secret, decodedData)
+	}
+
+	for k, v := range o.visibleData(secret) {
+		decodedData[k] = v
+	}
+
+	if len(decodedData) == 0 && !o.seeded() && !o.creating {
 		return nil, fmt.Errorf("secret %s has no data", o.secretName)
 	}
 
+	if o.fzf && o.terminal(o.streams.In) {
+		return o.selectDecodedData(decodedData)
+	}
+
 	return decodedData, nil
 }
 
-// extractSingleKey extracts a single key from the secret
-func (o *EditSecretOptions) extractSingleKey(secret *corev1.Secret, decodedData map[string]string) (map[string]string, error) {
-	if data, ok := secret.Data[o.key]; ok {
-		decodedData[o.key] = string(data)
-		return decodedData, nil
+// extractMatchingKeys extracts the keys matching the KEY glob pattern
+func (o *EditSecretOptions) extractMatchingKeys(secret *corev1.Secret, decodedData map[string]string) (map[string]string, error) {
+	for k, v := range o.visibleData(secret) {
+		matched, err := path.Match(o.keyPattern, k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q: %w", o.keyPattern, err)
+		}
+		if matched {
+			decodedData[k] = v
+		}
 	}
 
-	if strData, ok := secret.StringData[o.key]; ok {
-		decodedData[o.key] = strData
-		return decodedData, nil
+	if len(decodedData) == 0 {
+		return nil, fmt.Errorf("no keys match %q in secret. Available keys: %s", o.keyPattern, strings.Join(sortedKeys(secret.Data), ", "))
 	}
+	return decodedData, nil
+}
 
-	keys := make([]string, 0, len(secret.Data))
-	for k := range secret.Data {
+// selectDecodedData narrows decodedData to the keys the user picks.
+// Keys that are not picked are left out of the buffer and stay untouched.
+func (o *EditSecretOptions) selectDecodedData(decodedData map[string]string) (map[string]string, error) {
+	keys := make([]string, 0, len(decodedData))
+	for k := range decodedData {
 		keys = append(keys, k)
 	}
-	sort.Strings(keys)
-	return nil, fmt.Errorf("key %q not found in secret. Available keys: %s", o.key, strings.Join(keys, ", "))
+
+	selected, err := o.selectKeys("Select keys to edit", keys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(selected))
+	for _, k := range selected {
+		result[k] = decodedData[k]
+	}
+	return result, nil
 }
 
-// editInEditor opens the editor and returns edited data, or nil if cancelled
-func (o *EditSecretOptions) editInEditor(decodedData map[string]string) (map[string]string, error) {
-	editContent := o.createEditContent(decodedData)
+// visibleData returns the decoded values of the secret. StringData entries
+// are included and override Data, as they do when the API server stores the
+// secret, unless --source=data is set.
+func (o *EditSecretOptions) visibleData(secret *corev1.Secret) map[string]string {
+	data := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+	if o.source != sourceData {
+		for k, v := range secret.StringData {
+			data[k] = v
+		}
+	}
+	return data
+}
 
-	tmpPath, err := o.writeTempFile(editContent)
+// extractSingleKey extracts a single key from the secret, reading from Data,
+// StringData, or (in auto mode) StringData first since the API server lets it
+// override Data on write
+func (o *EditSecretOptions) extractSingleKey(secret *corev1.Secret, decodedData map[string]string) (map[string]string, error) {
+	value, err := o.lookupKey(secret, o.key)
 	if err != nil {
 		return nil, err
 	}
-	defer os.Remove(tmpPath)
+	decodedData[o.key] = value
+	return decodedData, nil
+}
 
-	beforeContent, err := os.ReadFile(tmpPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read temp file: %w", err)
+// extractKeys extracts each key given with --key
+func (o *EditSecretOptions) extractKeys(secret *corev1.Secret, decodedData map[string]string) (map[string]string, error) {
+	for _, k := range o.keys {
+		value, err := o.lookupKey(secret, k)
+		if err != nil {
+			return nil, err
+		}
+		decodedData[k] = value
+	}
+	return decodedData, nil
+}
+
+// lookupKey returns the decoded value of key, read according to --source
+func (o *EditSecretOptions) lookupKey(secret *corev1.Secret, key string) (string, error) {
+	if o.source != sourceData {
+		if strData, ok := secret.StringData[key]; ok {
+			return strData, nil
+		}
+	}
+
+	if o.source != sourceStringData {
+		if data, ok := secret.Data[key]; ok {
+			return string(data), nil
+		}
 	}
 
-	if err := o.runEditor(tmpPath); err != nil {
+	if o.source == sourceStringData {
+		keys := make([]string, 0, len(secret.StringData))
+		for k := range secret.StringData {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return "", fmt.Errorf("key %q not found in secret stringData. Available keys: %s", key, strings.Join(keys, ", "))
+	}
+
+	return "", fmt.Errorf("key %q not found in secret. Available keys: %s", key, strings.Join(sortedKeys(secret.Data), ", "))
+}
+
+// editInEditor opens the editor and returns edited data, or nil if cancelled.
+// If the buffer cannot be parsed or check rejects the edited values, the user
+// is offered to reopen the editor on the same file, up to maxEditAttempts
+// times; otherwise the file is kept so the edits are not lost.
+func (o *EditSecretOptions) editInEditor(decodedData map[string]string, check func(map[string]string) error) (map[string]string, error) {
+	editContent, err := o.createEditContent(decodedData)
+	if err != nil {
 		return nil, err
 	}
 
-	afterContent, err := os.ReadFile(tmpPath)
+	tmpPath, err := o.writeTempFile(editContent)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read temp file after edit: %w", err)
+		return nil, err
 	}
+	o.trackTempFile(tmpPath)
+	keep := false
+	defer func() {
+		if !keep {
+			os.Remove(tmpPath)
+		}
+		o.trackTempFile("")
+	}()
+
+	for attempt := 1; ; attempt++ {
+		if err := o.runEditor(tmpPath); err != nil {
+			return nil, err
+		}
+
+		if o.retryEditor {
+			choice, err := o.chooseAfterEdit()
+			if err != nil {
+				return nil, err
+			}
+			if choice == "cancel" {
+				return nil, errEditAborted
+			}
+			if choice == "edit" {
+				continue
+			}
+		}
 
-	if bytes.Equal(beforeContent, afterContent) {
+		edited, editErr := o.readEdited(tmpPath, editContent, decodedData)
+		if editErr == nil && edited != nil && check != nil {
+			editErr = check(edited)
+		}
+		if editErr == nil || errors.Is(editErr, errEditAborted) {
+			return edited, editErr
+		}
+
+		reopen := false
+		if o.reopenOnError && attempt < maxEditAttempts && o.terminal(o.streams.In) {
+			fmt.Fprintf(o.streams.ErrOut, "Error: %v\n", editErr)
+			if reopen, err = o.confirm("Reopen the editor to fix it? (empty the file to abort)"); err != nil {
+				return nil, err
+			}
+		}
+		if !reopen {
+			keep = true
+			return nil, fmt.Errorf("%w; your edits were kept in %s", editErr, tmpPath)
+		}
+	}
+}
+
+// maxEditAttempts caps how often the editor is reopened on an invalid buffer
+const maxEditAttempts = 5
+
+// readEdited reads and parses the edited file, or returns nil if it was not
+// modified
+func (o *EditSecretOptions) readEdited(tmpPath, editContent string, decodedData map[string]string) (map[string]string, error) {
+	afterContent, changed, err := readIfChanged(tmpPath, editContent)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
 		return nil, nil
 	}
+	// Emptying the whole file, header included, cancels the edit
+	if len(bytes.TrimSpace(afterContent)) == 0 {
+		return nil, errEditAborted
+	}
 
-	return parseEditedContent(afterContent)
+	var edited map[string]string
+	if o.delimited {
+		edited, err = parseDelimited(afterContent)
+	} else if o.format == formatJSON {
+		edited, err = parseJSONContent(afterContent)
+	} else if o.withMetadata {
+		edited, o.editedMetadata, err = parseMetadataContent(afterContent)
+	} else {
+		edited, err = parseEditedContent(afterContent)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeBinaryValues(edited, binaryKeys(decodedData)); err != nil {
+		return nil, err
+	}
+	return edited, nil
+}
+
+// chooseAfterEdit asks whether to apply the edit, reopen the editor on the
+// same file, or cancel
+func (o *EditSecretOptions) chooseAfterEdit() (string, error) {
+	for {
+		fmt.Fprint(o.streams.ErrOut, "Apply changes, edit again, or cancel? [a/e/c]: ")
+		answer, err := o.readLine()
+		if err != nil {
+			return "", fmt.Errorf("failed to read answer: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a", "apply":
+			return "apply", nil
+		case "e", "edit":
+			return "edit", nil
+		case "c", "cancel":
+			return "cancel", nil
+		}
+	}
+}
+
+// readIfChanged reads the edited file and reports whether it differs from the
+// original content. The original is compared from memory, so the file is read
+// only once; bytes.Equal rejects a size change before comparing any content.
+func readIfChanged(path, original string) ([]byte, bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read temp file after edit: %w", err)
+	}
+	return content, !bytes.Equal(content, []byte(original)), nil
 }
 
-// createEditContent creates the YAML content with header comments
-func (o *EditSecretOptions) createEditContent(decodedData map[string]string) string {
-	yamlContent, _ := yaml.Marshal(decodedData)
+// headerSentinel ends the comment header of the edit buffer
+const headerSentinel = "# --- end of kubectl-edit-secret header ---"
+
+// createEditContent creates the edit buffer content with header comments
+func (o *EditSecretOptions) createEditContent(decodedData map[string]string) (string, error) {
+	binary := binaryKeys(decodedData)
+	view := encodeBinaryValues(decodedData, binary)
+
+	var body string
+	instructions := "# Modify the values below. Comments you add are kept while editing,\n# but comments cannot be stored in the secret."
+	if o.delimited {
+		var err error
+		if body, err = renderDelimited(view); err != nil {
+			return "", err
+		}
+		instructions = "# Each value is the text between its BEGIN and END lines, kept exactly as written."
+	} else if o.format == formatJSON {
+		var err error
+		if body, err = renderJSONBuffer(view); err != nil {
+			return "", fmt.Errorf("failed to render secret data: %w", err)
+		}
+		instructions = "# Modify the values below. The buffer must stay a JSON object of strings;\n# JSON has no comments, so lines starting with '//' are only allowed up here."
+	} else if o.withMetadata {
+		yamlContent, err := renderMetadataBuffer(view, binary, o.metadata)
+		if err != nil {
+			return "", fmt.Errorf("failed to render secret data: %w", err)
+		}
+		body = string(yamlContent)
+		instructions += "\n# The values are under data; labels and annotations are under metadata."
+	} else {
+		yamlContent, err := renderYAMLBuffer(view, binary)
+		if err != nil {
+			return "", fmt.Errorf("failed to render secret data: %w", err)
+		}
+		body = string(yamlContent)
+	}
+	if len(binary) > 0 {
+		instructions += fmt.Sprintf("\n# Binary values are shown base64-encoded and must stay that way: %s", strings.Join(binary, ", "))
+	}
+	if len(o.base64Keys) > 0 {
+		instructions += fmt.Sprintf("\n# Stored base64-encoded, shown decoded and re-encoded on save: %s", strings.Join(o.base64Keys, ", "))
+	}
 
 	header := fmt.Sprintf(`# Editing secret: %s
 # Namespace: %s
+# Context: %s
+# Server: %s
 # 
-# Modify the values below. Lines starting with '#' are ignored.
+%s
 # The values shown are DECODED (plain text).
 # They will be automatically base64-encoded when saved.
 #
 # Save and exit to apply changes. Exit without saving to cancel.
-#
-`, o.secretName, o.namespace)
+%s
+`, o.secretName, o.namespace, o.contextName, o.server, instructions, headerSentinel)
 
-	return header + string(yamlContent)
+	if o.format == formatJSON {
+		header = jsonHeader(header)
+	}
+	return header + body, nil
 }
 
 // writeTempFile creates a temporary file with the given content
 func (o *EditSecretOptions) writeTempFile(content string) (string, error) {
-	tmpFile, err := os.CreateTemp("", fmt.Sprintf("kubectl-edit-secret-%s-*.yaml", o.secretName))
+	ext := "yaml"
+	if o.format == formatJSON {
+		ext = "json"
+	}
+	tmpFile, err := os.CreateTemp("", fmt.Sprintf("kubectl-edit-secret-%s-*.%s", o.secretName, ext))
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
@@ -283,22 +1498,123 @@ func (o *EditSecretOptions) writeTempFile(content string) (string, error) {
 	return tmpPath, nil
 }
 
-// runEditor opens the editor with the given file
+// runEditor opens the editor with the given file. A non-zero exit returns
+// errEditAborted unless --strict-editor is set.
 func (o *EditSecretOptions) runEditor(filePath string) error {
-	editorPath, editorArgs := parseEditor(o.editor)
+	editorPath, editorArgs, err := parseEditor(o.editor)
+	if err != nil {
+		return err
+	}
+	if !o.noAutoWait {
+		editorArgs = withWaitFlag(editorPath, editorArgs)
+	}
 	editorArgs = append(editorArgs, filePath)
 
-	cmd := exec.Command(editorPath, editorArgs...)
-	cmd.Stdin = os.Stdin
+	stdin, closeStdin := o.editorStdin()
+	defer closeStdin()
+
+	klog.V(2).Infof("Running editor %s %s", editorPath, strings.Join(editorArgs, " "))
+	cmd := o.editorCmd(editorPath, editorArgs...)
+	cmd.Stdin = stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if o.cleanEnv {
+		cmd.Env = cleanEditorEnv()
+	}
 
-	if err := cmd.Run(); err != nil {
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+	o.trackEditor(cmd.Process)
+	defer o.trackEditor(nil)
+
+	if err := cmd.Wait(); err != nil {
+		// A non-zero exit is how editors signal an intentional abort, such
+		// as :cq in vim; the edits are discarded rather than reported as a
+		// failure unless --strict-editor is set
+		if o.wasCancelled() {
+			return context.Canceled
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && !o.strictEditor {
+			fmt.Fprintf(o.streams.ErrOut, "Editor exited with %v, discarding the edit.\n", exitErr)
+			return errEditAborted
+		}
 		return fmt.Errorf("editor failed: %w", err)
 	}
 	return nil
 }
 
+// confirmDoubleEncoded asks for confirmation when a changed value looks like it
+// was pasted already base64-encoded. It returns true if there is nothing to
+// confirm or the user agrees to apply anyway.
+func (o *EditSecretOptions) confirmDoubleEncoded(original, edited map[string]string) (bool, error) {
+	suspects := make([]string, 0)
+	for k, v := range edited {
+		if oldVal, ok := original[k]; ok && oldVal == v {
+			continue
+		}
+		if looksBase64Encoded(v) {
+			suspects = append(suspects, k)
+		}
+	}
+
+	if len(suspects) == 0 {
+		return true, nil
+	}
+
+	sort.Strings(suspects)
+	fmt.Fprintf(o.streams.ErrOut, "Warning: values for %s look base64-encoded. Values are encoded automatically on save, so they would be stored double-encoded.\n", strings.Join(suspects, ", "))
+	return o.confirm("Apply anyway?")
+}
+
+// editorEnvAllowlist lists the environment variables kept by --editor-clean-env
+var editorEnvAllowlist = []string{
+	"HOME", "USER", "LOGNAME", "TERM", "PATH", "LANG", "LC_ALL", "TMPDIR",
+	"SYSTEMROOT", "USERPROFILE", "APPDATA", "LOCALAPPDATA", "TEMP", "TMP",
+}
+
+// cleanEditorEnv returns a minimal environment for the editor subprocess
+func cleanEditorEnv() []string {
+	env := make([]string, 0, len(editorEnvAllowlist))
+	for _, name := range editorEnvAllowlist {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// warnOversizedKeys lists the keys whose value exceeds --warn-key-size bytes
+func (o *EditSecretOptions) warnOversizedKeys(data map[string]string) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if size := len(data[k]); size > o.warnKeySize {
+			fmt.Fprintf(o.streams.ErrOut, "Warning: key %q is %d bytes, over the %d byte --warn-key-size limit\n", k, size, o.warnKeySize)
+		}
+	}
+}
+
+// printEncodedPreview prints the base64 value that will be stored for every
+// added or changed key, so encoding surprises like trailing newlines show up
+func (o *EditSecretOptions) printEncodedPreview(original, edited map[string]string) {
+	cs := o.changes(original, edited)
+
+	keys := append(append([]string(nil), cs.Added...), cs.Changed...)
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(o.streams.ErrOut, "%s: %s\n", k, base64.StdEncoding.EncodeToString([]byte(edited[k])))
+	}
+	for _, k := range cs.Removed {
+		fmt.Fprintf(o.streams.ErrOut, "%s: (removed)\n", k)
+	}
+}
+
 // hasChanges checks if the edited data differs from the original
 func (o *EditSecretOptions) hasChanges(original, edited map[string]string) bool {
 	if len(original) != len(edited) {
@@ -314,60 +1630,164 @@ func (o *EditSecretOptions) hasChanges(original, edited map[string]string) bool
 	return false
 }
 
-// applyChanges updates the secret with the edited data
-func (o *EditSecretOptions) applyChanges(ctx context.Context, secret *corev1.Secret, original, edited map[string]string) error {
-	if secret.Data == nil {
-		secret.Data = make(map[string][]byte)
+// metadataChanged reports whether labels or annotations were edited with
+// --with-metadata
+func (o *EditSecretOptions) metadataChanged() bool {
+	return o.editedMetadata != nil && !o.metadata.equal(o.editedMetadata)
+}
+
+// applyChanges updates the secret with the edited data and returns the
+// secret as stored. With --dry-run=client nothing is sent and the locally
+// modified secret is returned.
+func (o *EditSecretOptions) applyChanges(ctx context.Context, secret *corev1.Secret, original, edited map[string]string) (*corev1.Secret, error) {
+	// A dry run leaves the secret as it is, so there is nothing to back up
+	if o.backupPath != "" && !o.creating && o.dryRun == dryRunNone {
+		if err := o.writeBackup(secret); err != nil {
+			return nil, err
+		}
 	}
 
-	if o.key != "" {
-		if newVal, ok := edited[o.key]; ok {
-			secret.Data[o.key] = []byte(newVal)
+	if o.snapshot {
+		if err := recordSnapshot(secret); err != nil {
+			return nil, err
 		}
-	} else {
-		for k := range original {
-			if _, exists := edited[k]; !exists {
-				delete(secret.Data, k)
-			}
+	}
+
+	var unedited *corev1.Secret
+	if o.forceRecreate {
+		unedited = secret.DeepCopy()
+	}
+
+	cs := o.changes(original, edited)
+	base := maps.Clone(secret.Data)
+	klog.V(3).Infof("Changed keys: added %v, changed %v, removed %v", cs.Added, cs.Changed, cs.Removed)
+	if err := o.applyChangeSet(secret, cs, edited); err != nil {
+		return nil, err
+	}
+	if err := o.checkSecretSize(secret); err != nil {
+		return nil, err
+	}
+
+	if o.expectRV != "" {
+		secret.ResourceVersion = o.expectRV
+	}
+
+	if o.cloneTo != "" {
+		secret = o.cloneSecret(secret)
+	}
+
+	if o.dryRun == dryRunClient {
+		return secret, nil
+	}
+
+	if o.forceRecreate {
+		return o.recreateSecret(ctx, unedited, secret)
+	}
+
+	ctx, cancel := o.withTimeout(ctx)
+	defer cancel()
+
+	if o.creating {
+		return o.createSecret(ctx, secret)
+	}
+
+	if o.cloneTo != "" {
+		return o.writeClone(ctx, secret)
+	}
+
+	if o.serverSide {
+		return o.applyServerSide(ctx, secret, cs)
+	}
+
+	updated, err := o.clientset.CoreV1().Secrets(o.namespace).Update(ctx, secret, o.updateOptions())
+	if apierrors.IsConflict(err) {
+		if o.expectRV != "" {
+			return nil, o.resourceVersionMismatch("")
 		}
-		for k, v := range edited {
-			secret.Data[k] = []byte(v)
+		if o.retryOnConflict {
+			return o.retryConflict(ctx, base, cs, edited)
 		}
+		return o.resolveConflict(ctx, base, secret, cs, edited)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret: %w", o.forbiddenError("update", err))
 	}
 
-	secret.StringData = nil
+	return updated, nil
+}
 
-	_, err := o.clientset.CoreV1().Secrets(o.namespace).Update(ctx, secret, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to update secret: %w", err)
+// updateOptions returns the options for Update calls, honoring --dry-run=server
+func (o *EditSecretOptions) updateOptions() metav1.UpdateOptions {
+	if o.dryRun == dryRunServer {
+		return metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}}
+	}
+	return metav1.UpdateOptions{}
+}
+
+// applyChangeSet writes the change set and any labels and annotations edited
+// with --with-metadata into the secret, and records the resulting
+// configuration if --record is set
+func (o *EditSecretOptions) applyChangeSet(secret *corev1.Secret, cs changeSet, edited map[string]string) error {
+	writeChangeSet(secret, cs, edited)
+	if o.editedMetadata != nil {
+		applyMetadata(secret, o.editedMetadata)
 	}
 
+	if o.record {
+		return recordLastApplied(secret)
+	}
 	return nil
 }
 
-// parseEditor parses the editor command into path and arguments
-func parseEditor(editor string) (string, []string) {
-	parts := strings.Fields(editor)
+// writeChangeSet writes the added, changed, and removed keys into the secret
+func writeChangeSet(secret *corev1.Secret, cs changeSet, edited map[string]string) {
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+
+	// StringData is write-only and overrides Data when the server stores the
+	// secret. It is folded into Data first, so its keys are kept when it is
+	// cleared below and the change set applies on top of it.
+	for k, v := range secret.StringData {
+		secret.Data[k] = []byte(v)
+	}
+
+	for _, k := range cs.Removed {
+		delete(secret.Data, k)
+	}
+	for _, k := range cs.Added {
+		secret.Data[k] = []byte(edited[k])
+	}
+	for _, k := range cs.Changed {
+		secret.Data[k] = []byte(edited[k])
+	}
+
+	secret.StringData = nil
+}
+
+// parseEditor parses the editor command into path and arguments. Quotes
+// group words, so paths with spaces can be given as "/path with space/editor".
+func parseEditor(editor string) (string, []string, error) {
+	parts, err := splitCommand(editor)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid command %q: %w", editor, err)
+	}
 	if len(parts) == 0 {
-		return editor, nil
+		return editor, nil, nil
 	}
-	return parts[0], parts[1:]
+	return parts[0], parts[1:], nil
 }
 
-// parseEditedContent parses the YAML content, ignoring comments
+// parseEditedContent parses the YAML content. The header, up to and including
+// headerSentinel, is dropped; any other comments are left to the YAML parser,
+// so '#' lines inside block values stay part of the value.
 func parseEditedContent(content []byte) (map[string]string, error) {
-	lines := strings.Split(string(content), "\n")
-	cleanLines := make([]string, 0, len(lines))
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if !strings.HasPrefix(trimmed, "#") {
-			cleanLines = append(cleanLines, line)
-		}
+	if i := bytes.Index(content, []byte(headerSentinel)); i >= 0 {
+		content = content[i+len(headerSentinel):]
 	}
 
 	result := make(map[string]string)
-	if err := yaml.Unmarshal([]byte(strings.Join(cleanLines, "\n")), &result); err != nil {
+	if err := yaml.Unmarshal(content, &result); err != nil {
 		return nil, fmt.Errorf("invalid YAML: %w", err)
 	}
 
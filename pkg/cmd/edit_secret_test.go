@@ -0,0 +1,518 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// helperExitEnv tells TestEditorHelperProcess which exit code to use
+const helperExitEnv = "EDIT_SECRET_HELPER_EXIT"
+
+// helperBlockEnv makes TestEditorHelperProcess wait instead of exiting, like
+// an editor the user has not closed yet
+const helperBlockEnv = "EDIT_SECRET_HELPER_BLOCK"
+
+// TestEditorHelperProcess is not a real test. fakeEditor runs the test binary
+// with it as the editor process, so exit codes can be simulated portably.
+func TestEditorHelperProcess(t *testing.T) {
+	if os.Getenv(helperBlockEnv) != "" {
+		time.Sleep(time.Minute)
+	}
+	code := os.Getenv(helperExitEnv)
+	if code == "" {
+		return
+	}
+	n, _ := strconv.Atoi(code)
+	os.Exit(n)
+}
+
+// newTestOptions returns options with the flag defaults, a fake clientset
+// holding objects, and the buffers behind stdout and stderr
+func newTestOptions(t *testing.T, objects ...runtime.Object) (*EditSecretOptions, *fake.Clientset, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	o := NewEditSecretOptions(genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: errOut})
+	clientset := fake.NewSimpleClientset(objects...)
+	o.clientset = clientset
+	o.namespace = "default"
+	o.secretName = "db"
+	o.source = sourceAuto
+	o.format = formatYAML
+	o.importMode = importMerge
+	o.dryRun = dryRunNone
+	o.onConflict = conflictMerge
+	o.timeout = defaultTimeout
+	o.reopenOnError = true
+	o.editorCmd = func(name string, arg ...string) *exec.Cmd {
+		t.Fatalf("unexpected editor run: %s %s", name, strings.Join(arg, " "))
+		return nil
+	}
+	return o, clientset, out, errOut
+}
+
+// testSecret returns secret default/db with the given data
+func testSecret(data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", ResourceVersion: "1"},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       make(map[string][]byte, len(data)),
+	}
+	for k, v := range data {
+		secret.Data[k] = []byte(v)
+	}
+	return secret
+}
+
+// fakeEditor makes the editor rewrite the buffer with edit, in-process, and
+// then exit with exitCode. It returns a pointer to the number of runs.
+func fakeEditor(o *EditSecretOptions, exitCode int, edit func(run int, content string) string) *int {
+	runs := 0
+	o.editor = "fake-editor"
+	o.editorCmd = func(name string, arg ...string) *exec.Cmd {
+		runs++
+		path := arg[len(arg)-1]
+		content, err := os.ReadFile(path)
+		if err != nil {
+			panic(err)
+		}
+		if err := os.WriteFile(path, []byte(edit(runs, string(content))), 0o600); err != nil {
+			panic(err)
+		}
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestEditorHelperProcess$")
+		cmd.Env = append(os.Environ(), helperExitEnv+"="+strconv.Itoa(exitCode))
+		return cmd
+	}
+	return &runs
+}
+
+// replaceValue returns an edit that replaces old with replacement in the buffer
+func replaceValue(old, replacement string) func(int, string) string {
+	return func(_ int, content string) string {
+		return strings.Replace(content, old, replacement, 1)
+	}
+}
+
+// storedData returns the data of secret default/db as stored in clientset
+func storedData(t *testing.T, clientset *fake.Clientset) map[string]string {
+	t.Helper()
+	return storedSecretData(t, clientset, "db")
+}
+
+// storedSecretData returns the data of secret default/name as stored in
+// clientset
+func storedSecretData(t *testing.T, clientset *fake.Clientset, name string) map[string]string {
+	t.Helper()
+
+	secret, err := clientset.Tracker().Get(corev1.SchemeGroupVersion.WithResource("secrets"), "default", name)
+	if err != nil {
+		t.Fatalf("getting stored secret %s: %v", name, err)
+	}
+	return decodeData(secret.(*corev1.Secret))
+}
+
+func TestRunEditorNonZeroExitAborts(t *testing.T) {
+	o, clientset, out, errOut := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	fakeEditor(o, 1, replaceValue("password: old", "password: new"))
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+	if got := out.String(); got != "Aborted.\n" {
+		t.Errorf("stdout = %q, want %q", got, "Aborted.\n")
+	}
+	if !strings.Contains(errOut.String(), "discarding the edit") {
+		t.Errorf("stderr = %q, want a note that the edit was discarded", errOut.String())
+	}
+	if got := storedData(t, clientset)["password"]; got != "old" {
+		t.Errorf("stored password = %q, want it unchanged", got)
+	}
+}
+
+func TestRunEditorNonZeroExitStrict(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	o.strictEditor = true
+	fakeEditor(o, 1, replaceValue("password: old", "password: new"))
+
+	err := o.Run()
+	if err == nil || !strings.Contains(err.Error(), "editor failed") {
+		t.Fatalf("Run() error = %v, want an editor failure", err)
+	}
+	if got := storedData(t, clientset)["password"]; got != "old" {
+		t.Errorf("stored password = %q, want it unchanged", got)
+	}
+}
+
+func TestRunEditorSuccess(t *testing.T) {
+	o, clientset, out, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := out.String(); got != "secret/db edited\n" {
+		t.Errorf("stdout = %q, want the success line", got)
+	}
+	if got := storedData(t, clientset)["password"]; got != "new" {
+		t.Errorf("stored password = %q, want %q", got, "new")
+	}
+}
+
+func TestReadIfChanged(t *testing.T) {
+	const original = "password: old\n"
+	tests := []struct {
+		name        string
+		content     string
+		wantChanged bool
+	}{
+		{"unchanged", original, false},
+		{"same size", "password: new\n", true},
+		{"longer", "password: older\n", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "buffer.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			content, changed, err := readIfChanged(path, original)
+			if err != nil {
+				t.Fatalf("readIfChanged() error = %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if string(content) != tt.content {
+				t.Errorf("content = %q, want %q", content, tt.content)
+			}
+		})
+	}
+}
+
+func BenchmarkReadIfChanged(b *testing.B) {
+	original := strings.Repeat("key: "+strings.Repeat("x", 1019)+"\n", 1024)
+	path := filepath.Join(b.TempDir(), "buffer.yaml")
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		b.Fatal(err)
+	}
+
+	// The previous approach read the file back before opening the editor and
+	// compared it with the file read after editing
+	b.Run("read before and after", func(b *testing.B) {
+		b.SetBytes(int64(len(original)))
+		for i := 0; i < b.N; i++ {
+			before, err := os.ReadFile(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			after, err := os.ReadFile(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if !bytes.Equal(before, after) {
+				b.Fatal("file changed")
+			}
+		}
+	})
+
+	b.Run("compare from memory", func(b *testing.B) {
+		b.SetBytes(int64(len(original)))
+		for i := 0; i < b.N; i++ {
+			if _, changed, err := readIfChanged(path, original); err != nil || changed {
+				b.Fatalf("readIfChanged() = %v, %v", changed, err)
+			}
+		}
+	})
+}
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     func(int, string) string
+		wantCode int
+	}{
+		{"not modified", func(_ int, content string) string { return content }, ExitCodeNotEdited},
+		{"same values", func(_ int, content string) string { return content + "# a note\n" }, ExitCodeUnchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+			o.exitCode = true
+			fakeEditor(o, 0, tt.edit)
+
+			err := o.Run()
+			var exitErr *ExitError
+			if !errors.As(err, &exitErr) || exitErr.Code != tt.wantCode {
+				t.Fatalf("Run() error = %v, want exit code %d", err, tt.wantCode)
+			}
+			for _, action := range clientset.Actions() {
+				if action.GetVerb() == "update" {
+					t.Errorf("unexpected update of an unchanged secret")
+				}
+			}
+
+			// Without --exit-code both are a success
+			o, _, _, _ = newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+			fakeEditor(o, 0, tt.edit)
+			if err := o.Run(); err != nil {
+				t.Errorf("Run() without --exit-code error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestLookupEditor(t *testing.T) {
+	// PATH holds only a fake nano, so the fallback is predictable
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "nano"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		flag       string
+		kubeEditor string
+		editor     string
+		want       string
+		wantSource string
+	}{
+		{"flag", "code --wait", "vim", "emacs", "code --wait", "--editor flag"},
+		{"KUBE_EDITOR", "", "vim", "emacs", "vim", "$KUBE_EDITOR"},
+		{"EDITOR", "", "", "emacs", "emacs", "$EDITOR"},
+		{"fallback", "", "", "", "nano", "fallback:nano"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBE_EDITOR", tt.kubeEditor)
+			t.Setenv("EDITOR", tt.editor)
+			t.Setenv("PATH", bin)
+			o := &EditSecretOptions{editor: tt.flag}
+
+			if err := o.lookupEditor(); err != nil {
+				t.Fatalf("lookupEditor() error = %v", err)
+			}
+			if o.editor != tt.want || o.editorSource != tt.wantSource {
+				t.Errorf("editor = %q from %q, want %q from %q", o.editor, o.editorSource, tt.want, tt.wantSource)
+			}
+		})
+	}
+
+	t.Run("none", func(t *testing.T) {
+		t.Setenv("KUBE_EDITOR", "")
+		t.Setenv("EDITOR", "")
+		t.Setenv("PATH", t.TempDir())
+
+		if err := (&EditSecretOptions{}).lookupEditor(); err == nil {
+			t.Error("lookupEditor() error = nil, want an error when no editor is found")
+		}
+	})
+}
+
+func TestLookupKey(t *testing.T) {
+	secret := testSecret(map[string]string{"password": "from-data", "user": "admin"})
+	secret.StringData = map[string]string{"password": "from-stringdata"}
+
+	tests := []struct {
+		source  string
+		key     string
+		want    string
+		wantErr string
+	}{
+		{sourceAuto, "password", "from-stringdata", ""},
+		{sourceData, "password", "from-data", ""},
+		{sourceStringData, "password", "from-stringdata", ""},
+		{sourceAuto, "user", "admin", ""},
+		{sourceStringData, "user", "", "not found in secret stringData"},
+		{sourceData, "missing", "", "Available keys: password, user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source+"/"+tt.key, func(t *testing.T) {
+			o := &EditSecretOptions{source: tt.source}
+			got, err := o.lookupKey(secret, tt.key)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("lookupKey() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("lookupKey() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("lookupKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunImmutableFailsBeforeEditor(t *testing.T) {
+	secret := testSecret(map[string]string{"password": "old"})
+	immutable := true
+	secret.Immutable = &immutable
+	// newTestOptions fails the test if the editor is started
+	o, _, _, _ := newTestOptions(t, secret)
+
+	err := o.Run()
+	if err == nil || !strings.Contains(err.Error(), "secret db is immutable") {
+		t.Fatalf("Run() error = %v, want the immutable error", err)
+	}
+}
+
+func TestVisibleData(t *testing.T) {
+	secret := testSecret(map[string]string{"password": "from-data", "user": "admin"})
+	secret.StringData = map[string]string{"password": "from-stringdata", "token": "t1"}
+
+	tests := []struct {
+		source string
+		want   map[string]string
+	}{
+		{sourceAuto, map[string]string{"password": "from-stringdata", "user": "admin", "token": "t1"}},
+		{sourceStringData, map[string]string{"password": "from-stringdata", "user": "admin", "token": "t1"}},
+		{sourceData, map[string]string{"password": "from-data", "user": "admin"}},
+	}
+
+	for _, tt := range tests {
+		o := &EditSecretOptions{source: tt.source}
+		if got := o.visibleData(secret); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("visibleData() with --source=%s = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestWriteChangeSetFoldsStringData(t *testing.T) {
+	secret := testSecret(map[string]string{"password": "from-data", "user": "admin", "old": "x"})
+	secret.StringData = map[string]string{"password": "from-stringdata", "token": "t1"}
+
+	edited := map[string]string{"user": "root", "new": "n"}
+	writeChangeSet(secret, changeSet{Added: []string{"new"}, Changed: []string{"user"}, Removed: []string{"old"}}, edited)
+
+	if secret.StringData != nil {
+		t.Errorf("stringData = %q, want it cleared", secret.StringData)
+	}
+	want := map[string]string{"password": "from-stringdata", "token": "t1", "user": "root", "new": "n"}
+	if got := decodeData(secret); !reflect.DeepEqual(got, want) {
+		t.Errorf("data = %q, want %q", got, want)
+	}
+}
+
+func TestRunReopensAfterParseError(t *testing.T) {
+	o, clientset, _, errOut := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	o.streams.In = strings.NewReader("y\n")
+	o.terminal = func(interface{}) bool { return true }
+	runs := fakeEditor(o, 0, func(run int, content string) string {
+		if run == 1 {
+			return strings.Replace(content, "password: old", "password: [new", 1)
+		}
+		return strings.Replace(content, "password: [new", "password: new", 1)
+	})
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if *runs != 2 {
+		t.Errorf("editor ran %d times, want 2", *runs)
+	}
+	if !strings.Contains(errOut.String(), "Error: invalid YAML") || !strings.Contains(errOut.String(), "Reopen the editor to fix it?") {
+		t.Errorf("stderr = %q, want the parse error and the reopen question", errOut.String())
+	}
+	if got := storedData(t, clientset)["password"]; got != "new" {
+		t.Errorf("stored password = %q, want %q", got, "new")
+	}
+}
+
+func TestRunKeepsFileOnParseErrorWithoutTerminal(t *testing.T) {
+	// The kept file lands in TMPDIR
+	t.Setenv("TMPDIR", t.TempDir())
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	runs := fakeEditor(o, 0, replaceValue("password: old", "password: [new"))
+
+	err := o.Run()
+	if err == nil || !strings.Contains(err.Error(), "your edits were kept in") {
+		t.Fatalf("Run() error = %v, want the kept file to be reported", err)
+	}
+	if *runs != 1 {
+		t.Errorf("editor ran %d times, want 1", *runs)
+	}
+	if got := storedData(t, clientset)["password"]; got != "old" {
+		t.Errorf("stored password = %q, want it unchanged", got)
+	}
+}
+
+func TestRunQuiet(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(int, string) string
+	}{
+		{"edited", replaceValue("password: old", "password: new")},
+		{"not modified", func(_ int, content string) string { return content }},
+		{"same values", func(_ int, content string) string { return content + "# a note\n" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, out, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+			o.quiet = true
+			fakeEditor(o, 0, tt.edit)
+
+			if err := o.Run(); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if out.Len() != 0 {
+				t.Errorf("stdout = %q, want it empty with --quiet", out.String())
+			}
+		})
+	}
+}
+
+func TestRunGlobKey(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"api-key": "k1", "db.host": "h", "db.password": "old"}))
+	// Complete moves a KEY with glob characters here when --glob-key is set
+	o.keyPattern = "db.*"
+	var buffer string
+	fakeEditor(o, 0, func(_ int, content string) string {
+		buffer = content
+		return strings.Replace(content, "db.password: old", "db.password: new", 1)
+	})
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !strings.Contains(buffer, "db.host: h") || strings.Contains(buffer, "api-key") {
+		t.Errorf("buffer =\n%s\nwant only the keys matching db.*", buffer)
+	}
+	want := map[string]string{"api-key": "k1", "db.host": "h", "db.password": "new"}
+	if got := storedData(t, clientset); !reflect.DeepEqual(got, want) {
+		t.Errorf("stored data = %q, want %q", got, want)
+	}
+}
+
+func TestRunGlobKeyNoMatch(t *testing.T) {
+	o, _, _, _ := newTestOptions(t, testSecret(map[string]string{"api-key": "k1", "db.host": "h"}))
+	o.keyPattern = "cache.*"
+
+	err := o.Run()
+	if want := `no keys match "cache.*" in secret. Available keys: api-key, db.host`; err == nil || err.Error() != want {
+		t.Errorf("Run() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// editorWaitFlags maps GUI editors that return before the file is closed to
+// the flag that makes them wait. Other spellings of the flag that are already
+// accepted are listed after the first.
+var editorWaitFlags = map[string][]string{
+	"code":          {"--wait", "-w"},
+	"code-insiders": {"--wait", "-w"},
+	"codium":        {"--wait", "-w"},
+	"cursor":        {"--wait", "-w"},
+	"subl":          {"-w", "--wait"},
+	"atom":          {"--wait", "-w"},
+	"mate":          {"-w", "--wait"},
+	"zed":           {"--wait", "-w"},
+}
+
+// withWaitFlag adds the wait flag for known GUI editors unless the arguments
+// already contain it
+func withWaitFlag(editorPath string, args []string) []string {
+	name := strings.ToLower(filepath.Base(editorPath))
+	for _, ext := range []string{".exe", ".cmd", ".bat"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	flags, ok := editorWaitFlags[name]
+	if !ok {
+		return args
+	}
+	for _, arg := range args {
+		for _, f := range flags {
+			if arg == f {
+				return args
+			}
+		}
+	}
+	return append([]string{flags[0]}, args...)
+}
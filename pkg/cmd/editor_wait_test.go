@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithWaitFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		args []string
+		want []string
+	}{
+		{"code", "code", nil, []string{"--wait"}},
+		{"code with arguments", "code", []string{"-n"}, []string{"--wait", "-n"}},
+		{"full path", "/usr/local/bin/subl", nil, []string{"-w"}},
+		{"exe", "C:/Program Files/Microsoft VS Code/Code.exe", nil, []string{"--wait"}},
+		{"cmd", "code.cmd", []string{"-n"}, []string{"--wait", "-n"}},
+		{"wait already present", "code", []string{"--wait", "-n"}, []string{"--wait", "-n"}},
+		{"other spelling present", "code", []string{"-w"}, []string{"-w"}},
+		{"subl long flag present", "subl.exe", []string{"--wait"}, []string{"--wait"}},
+		{"terminal editor", "vim", []string{"-n"}, []string{"-n"}},
+		{"unknown exe", "notepad.exe", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withWaitFlag(tt.path, tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withWaitFlag(%q, %q) = %q, want %q", tt.path, tt.args, got, tt.want)
+			}
+		})
+	}
+}
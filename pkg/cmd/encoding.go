@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minBase64Length is the shortest value checked for accidental double encoding.
+// Shorter strings match the base64 alphabet too often to be meaningful.
+const minBase64Length = 8
+
+// looksBase64Encoded reports whether v is valid base64 that decodes to
+// printable text, which usually means an already-encoded value was pasted
+// into the decoded buffer
+func looksBase64Encoded(v string) bool {
+	v = strings.TrimSpace(v)
+	if len(v) < minBase64Length || len(v)%4 != 0 {
+		return false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return false
+	}
+	if !utf8.Valid(decoded) {
+		return false
+	}
+
+	for _, r := range string(decoded) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envNameRE matches names that are valid environment variable names
+var envNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// plainEnvValueRE matches values that can be written to a .env file unquoted
+var plainEnvValueRE = regexp.MustCompile(`^[-A-Za-z0-9_./:@%+,=]*$`)
+
+// envEscaper escapes a value for use inside double quotes in a .env file
+var envEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"$", `\$`,
+	"`", "\\`",
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+)
+
+// quoteEnvValue returns value as it should appear after KEY= in a .env file.
+// Simple values are left bare; anything else is double-quoted with escapes.
+func quoteEnvValue(value string) string {
+	if plainEnvValueRE.MatchString(value) {
+		return value
+	}
+	return `"` + envEscaper.Replace(value) + `"`
+}
+
+// writeEnv writes the decoded data as KEY=value lines. Keys that are not
+// valid environment variable names are skipped with a warning.
+func (o *EditSecretOptions) writeEnv(data map[string]string) error {
+	var b strings.Builder
+	for _, k := range sortedStringKeys(data) {
+		if !envNameRE.MatchString(k) {
+			fmt.Fprintf(o.streams.ErrOut, "Warning: skipping key %q, it is not a valid environment variable name\n", k)
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", k, quoteEnvValue(data[k]))
+	}
+	_, err := fmt.Fprint(o.streams.Out, b.String())
+	return err
+}
+
+const (
+	importMerge   = "merge"
+	importReplace = "replace"
+)
+
+// envUnescaper reverses the escapes accepted inside double-quoted values
+var envUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\"`, `"`,
+	`\$`, "$",
+	"\\`", "`",
+	`\n`, "\n",
+	`\r`, "\r",
+	`\t`, "\t",
+)
+
+// parseEnvFile parses dotenv content: KEY=value lines, blank lines, and #
+// comments. An optional "export " prefix is accepted. Values may be single
+// quoted (taken literally) or double quoted (with escapes); both may span
+// several lines. Later assignments of a key win.
+func parseEnvFile(content string) (map[string]string, error) {
+	data := map[string]string{}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, rest, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		if !envNameRE.MatchString(key) {
+			return nil, fmt.Errorf("line %d: %q is not a valid variable name", lineNo, key)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+
+		if rest == "" || (rest[0] != '"' && rest[0] != '\'') {
+			if idx := strings.Index(rest, " #"); idx >= 0 {
+				rest = rest[:idx]
+			}
+			data[key] = strings.TrimSpace(rest)
+			continue
+		}
+
+		quote := rest[0]
+		value := rest[1:]
+		for {
+			end := closingQuote(value, quote)
+			if end >= 0 {
+				trailing := strings.TrimSpace(value[end+1:])
+				if trailing != "" && !strings.HasPrefix(trailing, "#") {
+					return nil, fmt.Errorf("line %d: unexpected text after the closing quote of %s", lineNo, key)
+				}
+				value = value[:end]
+				break
+			}
+			i++
+			if i == len(lines) {
+				return nil, fmt.Errorf("line %d: missing closing quote for %s", lineNo, key)
+			}
+			value += "\n" + lines[i]
+		}
+		if quote == '"' {
+			value = envUnescaper.Replace(value)
+		}
+		data[key] = value
+	}
+	return data, nil
+}
+
+// closingQuote returns the index of the quote that closes s, or -1. Inside
+// double quotes a backslash escapes the next character.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// importEnv sets the keys of the --import-env file into the buffer. With
+// --import-mode=replace, keys missing from the file are removed.
+func (o *EditSecretOptions) importEnv(buffer map[string]string) error {
+	content, err := os.ReadFile(o.importEnvPath)
+	if err != nil {
+		return fmt.Errorf("failed to read --import-env file: %w", err)
+	}
+	imported, err := parseEnvFile(string(content))
+	if err != nil {
+		return fmt.Errorf("failed to parse --import-env file %s: %w", o.importEnvPath, err)
+	}
+
+	if o.importMode == importReplace {
+		for k := range buffer {
+			if _, ok := imported[k]; !ok {
+				delete(buffer, k)
+			}
+		}
+	}
+	for k, v := range imported {
+		buffer[k] = v
+	}
+	return nil
+}
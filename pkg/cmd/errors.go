@@ -0,0 +1,32 @@
+package cmd
+
+import "errors"
+
+// errEditAborted is returned when the user cancels from a prompt after editing
+// or the editor exits with a non-zero status
+var errEditAborted = errors.New("edit aborted")
+
+// Exit codes reported through ExitError
+const (
+	// ExitCodeNotEdited means the editor was closed without modifying the file
+	ExitCodeNotEdited = 3
+	// ExitCodeUnchanged means the file was modified but the values are the same
+	ExitCodeUnchanged = 4
+	// ExitCodeNotFound means the secret does not exist (with --fail-on-empty)
+	ExitCodeNotFound = 5
+	// ExitCodeEmpty means the secret exists but has no data (with --fail-on-empty)
+	ExitCodeEmpty = 6
+	// ExitCodeDiffers means the secrets compared with --diff-with differ
+	ExitCodeDiffers = 1
+)
+
+// ExitError carries a specific process exit code. Its message has already
+// been reported to the user, so it is not printed again.
+type ExitError struct {
+	Code int
+	Msg  string
+}
+
+func (e *ExitError) Error() string {
+	return e.Msg
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// eventReason is the reason of the events created with --emit-event
+const eventReason = "Edited"
+
+// emitEditEvent creates an Event on the written secret, at its new
+// resourceVersion, listing the changed key names and the acting user.
+// Failures, such as missing permission to create events, only produce a
+// warning since the edit itself has already been applied.
+func (o *EditSecretOptions) emitEditEvent(ctx context.Context, secret *corev1.Secret, cs changeSet) {
+	ctx, cancel := o.withTimeout(ctx)
+	defer cancel()
+
+	var parts []string
+	if len(cs.Added) > 0 {
+		parts = append(parts, "added "+strings.Join(cs.Added, ", "))
+	}
+	if len(cs.Changed) > 0 {
+		parts = append(parts, "changed "+strings.Join(cs.Changed, ", "))
+	}
+	if len(cs.Removed) > 0 {
+		parts = append(parts, "removed "+strings.Join(cs.Removed, ", "))
+	}
+
+	message := "Secret edited with kubectl-edit-secret"
+	if user := o.actingUser(ctx); user != "" {
+		message += " by " + user
+	}
+	if len(parts) > 0 {
+		message += ": " + strings.Join(parts, "; ")
+	}
+
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: secret.Name + ".",
+			Namespace:    o.namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      "v1",
+			Kind:            "Secret",
+			Name:            secret.Name,
+			Namespace:       o.namespace,
+			UID:             secret.UID,
+			ResourceVersion: secret.ResourceVersion,
+		},
+		Reason:         eventReason,
+		Message:        message,
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: "kubectl-edit-secret"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+
+	if _, err := o.clientset.CoreV1().Events(o.namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		fmt.Fprintf(o.streams.ErrOut, "Warning: failed to create event for secret %s: %v\n", secret.Name, err)
+	}
+}
+
+// actingUser returns the username the API server authenticates us as, or an
+// empty string if it cannot be determined
+func (o *EditSecretOptions) actingUser(ctx context.Context) string {
+	review, err := o.clientset.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return ""
+	}
+	return review.Status.UserInfo.Username
+}
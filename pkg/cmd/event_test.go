@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRunEmitEventReferencesUpdatedSecret(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	o.emitEvent = true
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+	clientset.PrependReactor("update", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updated := action.(k8stesting.UpdateAction).GetObject().(*corev1.Secret).DeepCopy()
+		updated.ResourceVersion = "2"
+		return true, updated, nil
+	})
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var event *corev1.Event
+	for _, action := range clientset.Actions() {
+		if action.Matches("create", "events") {
+			event = action.(k8stesting.CreateAction).GetObject().(*corev1.Event)
+		}
+	}
+	if event == nil {
+		t.Fatal("no event created")
+	}
+	if got := event.InvolvedObject.ResourceVersion; got != "2" {
+		t.Errorf("event resourceVersion = %q, want the updated %q", got, "2")
+	}
+	if event.Message != "Secret edited with kubectl-edit-secret: changed password" {
+		t.Errorf("event message = %q", event.Message)
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// unsafeFilenameRE matches characters replaced when a key becomes a file name
+var unsafeFilenameRE = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// keyFilename maps a key to a file name. Characters outside [-._a-zA-Z0-9]
+// are replaced with '_'. It returns false for names that cannot be used as a
+// file, such as "." and "..".
+func keyFilename(key string) (string, bool) {
+	name := unsafeFilenameRE.ReplaceAllString(key, "_")
+	if name == "" || name == "." || name == ".." {
+		return "", false
+	}
+	return name, true
+}
+
+// exportDir writes each decoded value of the secret to its own file in dir.
+// Existing files are never overwritten. File names are checked for clashes
+// and existing files before anything is written, so a failed export leaves
+// no partial set of files behind.
+func (o *EditSecretOptions) exportDir(secret *corev1.Secret) error {
+	type exportFile struct{ key, path string }
+
+	files := make([]exportFile, 0, len(secret.Data))
+	owners := make(map[string]string, len(secret.Data))
+	for _, k := range sortedKeys(secret.Data) {
+		name, ok := keyFilename(k)
+		if !ok {
+			fmt.Fprintf(o.streams.ErrOut, "Warning: skipping key %q, it is not a usable file name\n", k)
+			continue
+		}
+		if other, clash := owners[name]; clash {
+			return fmt.Errorf("keys %q and %q would both be exported to file %s; nothing was written", other, k, name)
+		}
+		owners[name] = k
+
+		path := filepath.Join(o.exportDirPath, name)
+		if _, err := os.Lstat(path); err == nil {
+			return fmt.Errorf("failed to export key %q: %s already exists; nothing was written", k, path)
+		}
+		files = append(files, exportFile{k, path})
+	}
+
+	if err := os.MkdirAll(o.exportDirPath, 0o700); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	for _, file := range files {
+		k, path := file.key, file.path
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to export key %q: %w", k, err)
+		}
+		if _, err := f.Write(secret.Data[k]); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to export key %q: %w", k, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to export key %q: %w", k, err)
+		}
+
+		o.info("%s -> %s\n", k, path)
+	}
+	return nil
+}
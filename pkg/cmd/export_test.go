@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportDir(t *testing.T) {
+	o, _, _, errOut := newTestOptions(t)
+	o.exportDirPath = filepath.Join(t.TempDir(), "db")
+	secret := testSecret(map[string]string{"password": "s3cr3t", "tls key": "k", "..": "skipped"})
+
+	if err := o.exportDir(secret); err != nil {
+		t.Fatalf("exportDir() error = %v", err)
+	}
+	for name, want := range map[string]string{"password": "s3cr3t", "tls_key": "k"} {
+		got, err := os.ReadFile(filepath.Join(o.exportDirPath, name))
+		if err != nil || string(got) != want {
+			t.Errorf("file %s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if !strings.Contains(errOut.String(), `skipping key ".."`) {
+		t.Errorf("stderr = %q, want the skipped key warning", errOut.String())
+	}
+}
+
+func TestExportDirNothingWrittenOnFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]string
+		existing string
+		wantErr  string
+	}{
+		{
+			name:    "clashing file names",
+			data:    map[string]string{"a b": "1", "a_b": "2", "password": "p"},
+			wantErr: `keys "a b" and "a_b" would both be exported to file a_b`,
+		},
+		{
+			name:     "existing file",
+			data:     map[string]string{"password": "p", "user": "u"},
+			existing: "user",
+			wantErr:  "already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, _, _ := newTestOptions(t)
+			o.exportDirPath = t.TempDir()
+			if tt.existing != "" {
+				if err := os.WriteFile(filepath.Join(o.exportDirPath, tt.existing), []byte("keep"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := o.exportDir(testSecret(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("exportDir() error = %v, want %q", err, tt.wantErr)
+			}
+			entries, err := os.ReadDir(o.exportDirPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantEntries := 0
+			if tt.existing != "" {
+				wantEntries = 1
+			}
+			if len(entries) != wantEntries {
+				t.Errorf("export directory has %d entries, want %d", len(entries), wantEntries)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+)
+
+// runFilter pipes value through the filter command and returns its output.
+// Values are only ever passed on stdin, never as arguments.
+func (o *EditSecretOptions) runFilter(filter, value string) (string, error) {
+	path, args, err := parseEditor(filter)
+	if err != nil {
+		return "", err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = bytes.NewBufferString(value)
+	cmd.Stdout = &stdout
+	cmd.Stderr = o.streams.ErrOut
+
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// decodeFilterValues runs every value through --decode-filter
+func (o *EditSecretOptions) decodeFilterValues(data map[string]string) error {
+	for k, v := range data {
+		out, err := o.runFilter(o.decodeFilter, v)
+		if err != nil {
+			return fmt.Errorf("--decode-filter failed for key %q: %w", k, err)
+		}
+		data[k] = out
+	}
+	return nil
+}
+
+// encodeFilterValues returns a copy of edited where every added or changed
+// value has been run through --encode-filter. Unchanged values are left as
+// they are, so they are not rewritten and round-trip exactly.
+func (o *EditSecretOptions) encodeFilterValues(original, edited map[string]string) (map[string]string, error) {
+	cs := o.changes(original, edited)
+	keys := append(append([]string(nil), cs.Added...), cs.Changed...)
+	sort.Strings(keys)
+
+	result := make(map[string]string, len(edited))
+	for k, v := range edited {
+		result[k] = v
+	}
+	for _, k := range keys {
+		out, err := o.runFilter(o.encodeFilter, edited[k])
+		if err != nil {
+			return nil, fmt.Errorf("--encode-filter failed for key %q: %w", k, err)
+		}
+		result[k] = out
+	}
+	return result, nil
+}
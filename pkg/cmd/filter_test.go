@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// rot13 is a filter that is its own inverse
+const rot13 = "tr a-zA-Z n-za-mN-ZA-M"
+
+func TestRunFilterRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("tr"); err != nil {
+		t.Skip("tr not found")
+	}
+
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"user": "nqzva", "password": "byq"}))
+	o.decodeFilter = rot13
+	o.encodeFilter = rot13
+	fakeEditor(o, 0, func(_ int, content string) string {
+		if !strings.Contains(content, "user: admin") {
+			t.Errorf("buffer = %q, want decoded values", content)
+		}
+		return strings.Replace(content, "user: admin", "user: root", 1)
+	})
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	stored := storedData(t, clientset)
+	if stored["user"] != "ebbg" {
+		t.Errorf("stored user = %q, want the encoded %q", stored["user"], "ebbg")
+	}
+	if stored["password"] != "byq" {
+		t.Errorf("stored password = %q, want it untouched", stored["password"])
+	}
+}
+
+func TestValidateOneSidedFilter(t *testing.T) {
+	for _, set := range []func(o *EditSecretOptions){
+		func(o *EditSecretOptions) { o.decodeFilter = rot13 },
+		func(o *EditSecretOptions) { o.encodeFilter = rot13 },
+	} {
+		o, _, _, _ := newTestOptions(t)
+		set(o)
+		err := o.Validate()
+		if err == nil || !strings.Contains(err.Error(), "must be used together") {
+			t.Errorf("Validate() error = %v, want the paired-filter error", err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// forbiddenUserRE extracts the user from the API server's forbidden message,
+// e.g. `User "jane" cannot get resource "secrets"`
+var forbiddenUserRE = regexp.MustCompile(`User "([^"]+)" cannot`)
+
+// forbiddenError adds RBAC guidance to an error returned when the user may not
+// perform verb on secrets. Other errors are returned unchanged.
+func (o *EditSecretOptions) forbiddenError(verb string, err error) error {
+	if !apierrors.IsForbidden(err) {
+		return err
+	}
+
+	who := "you are"
+	if m := forbiddenUserRE.FindStringSubmatch(err.Error()); m != nil {
+		who = describeUser(m[1]) + " is"
+	}
+	return fmt.Errorf("%w. Check that %s allowed to %s secrets in namespace %s, e.g. with: kubectl auth can-i %s secrets -n %s", err, who, verb, o.namespace, verb, o.namespace)
+}
+
+// describeUser names a user, spelling out service accounts as namespace/name
+func describeUser(user string) string {
+	if sa, ok := strings.CutPrefix(user, "system:serviceaccount:"); ok {
+		if ns, name, ok := strings.Cut(sa, ":"); ok {
+			return fmt.Sprintf("service account %s/%s", ns, name)
+		}
+	}
+	return fmt.Sprintf("user %q", user)
+}
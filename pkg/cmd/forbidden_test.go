@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// forbid makes every verb request on secrets fail as forbidden for user
+func forbid(clientset *fake.Clientset, verb, user string) {
+	clientset.PrependReactor(verb, "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reason := errors.New(`User "` + user + `" cannot ` + verb + ` resource "secrets" in API group "" in the namespace "default"`)
+		return true, nil, apierrors.NewForbidden(corev1.Resource("secrets"), "db", reason)
+	})
+}
+
+func TestRunForbidden(t *testing.T) {
+	tests := []struct {
+		name string
+		verb string
+		user string
+		want string
+	}{
+		{"get", "get", "jane", `Check that user "jane" is allowed to get secrets in namespace default, e.g. with: kubectl auth can-i get secrets -n default`},
+		{"update", "update", "system:serviceaccount:ci:deployer", "Check that service account ci/deployer is allowed to update secrets in namespace default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+			forbid(clientset, tt.verb, tt.user)
+			if tt.verb != "get" {
+				fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+			}
+
+			err := o.Run()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() error = %v, want %q", err, tt.want)
+			}
+			if !apierrors.IsForbidden(err) {
+				t.Errorf("Run() error = %v, want it to wrap the forbidden error", err)
+			}
+		})
+	}
+}
+
+func TestForbiddenErrorPassesOtherErrors(t *testing.T) {
+	o := &EditSecretOptions{namespace: "default"}
+	err := errors.New("connection refused")
+	if got := o.forbiddenError("get", err); got != err {
+		t.Errorf("forbiddenError() = %v, want the error unchanged", got)
+	}
+}
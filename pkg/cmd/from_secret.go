@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// parseSecretRef parses a [namespace/]name[:key] reference. The namespace
+// defaults to the given one and key is empty when not specified.
+func parseSecretRef(ref, defaultNamespace string) (namespace, name, key string, err error) {
+	namespace = defaultNamespace
+	name = ref
+
+	if i := strings.Index(name, ":"); i >= 0 {
+		name, key = name[:i], name[i+1:]
+		if key == "" {
+			return "", "", "", fmt.Errorf("invalid secret reference %q: empty key after ':'", ref)
+		}
+	}
+	if i := strings.Index(name, "/"); i >= 0 {
+		namespace, name = name[:i], name[i+1:]
+		if namespace == "" {
+			return "", "", "", fmt.Errorf("invalid secret reference %q: empty namespace", ref)
+		}
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return "", "", "", fmt.Errorf("invalid secret reference %q, expected [namespace/]name[:key]", ref)
+	}
+	return namespace, name, key, nil
+}
+
+// seedFromSecret copies one or all keys of the --from-secret source into the
+// edit buffer. Keys that already exist in the target require --overwrite.
+func (o *EditSecretOptions) seedFromSecret(ctx context.Context, target *corev1.Secret, buffer map[string]string) error {
+	namespace, name, key, err := parseSecretRef(o.fromSecret, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	source, err := o.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get source secret %s/%s: %w", namespace, name, err)
+	}
+
+	copied := source.Data
+	if key != "" {
+		value, ok := source.Data[key]
+		if !ok {
+			return fmt.Errorf("key %q not found in source secret %s/%s. Available keys: %s", key, namespace, name, strings.Join(sortedKeys(source.Data), ", "))
+		}
+		copied = map[string][]byte{key: value}
+	}
+
+	if len(copied) == 0 {
+		return fmt.Errorf("source secret %s/%s has no data", namespace, name)
+	}
+
+	if !o.overwrite {
+		collisions := make([]string, 0)
+		for k := range copied {
+			if _, exists := target.Data[k]; exists {
+				collisions = append(collisions, k)
+			}
+		}
+		if len(collisions) > 0 {
+			sort.Strings(collisions)
+			return fmt.Errorf("keys already exist in secret %s: %s. Use --overwrite to replace them", o.secretName, strings.Join(collisions, ", "))
+		}
+	}
+
+	for k, v := range copied {
+		buffer[k] = string(v)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of data in sorted order
+func sortedKeys(data map[string][]byte) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// maxURLContentSize caps content fetched with --from-url at the 1MiB
+	// secret size limit
+	maxURLContentSize = 1 << 20
+	// urlFetchTimeout bounds each --from-url request
+	urlFetchTimeout = 30 * time.Second
+	// maxURLRedirects is how many redirects a --from-url request follows
+	maxURLRedirects = 10
+)
+
+// parseKeyValue splits a key=value flag argument on the first '='
+func parseKeyValue(flag, arg string) (string, string, error) {
+	key, value, ok := strings.Cut(arg, "=")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid %s %q, expected key=value", flag, arg)
+	}
+	return key, value, nil
+}
+
+// validateFromURLs checks the --from-url arguments before anything is fetched
+func (o *EditSecretOptions) validateFromURLs() error {
+	for _, arg := range o.fromURLs {
+		key, rawURL, err := parseKeyValue("--from-url", arg)
+		if err != nil {
+			return err
+		}
+
+		// The URL may carry credentials, so errors show the redacted form,
+		// or only the key when it cannot be parsed
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid --from-url for key %q: the URL cannot be parsed", key)
+		}
+		if err := o.checkURLScheme(u); err != nil {
+			return fmt.Errorf("--from-url %s=%s %w", key, u.Redacted(), err)
+		}
+	}
+	return nil
+}
+
+// checkURLScheme allows https:// URLs, and http:// ones with --insecure-url
+func (o *EditSecretOptions) checkURLScheme(u *url.URL) error {
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && o.insecureURL:
+	case u.Scheme == "http":
+		return fmt.Errorf("uses plain HTTP; use HTTPS or pass --insecure-url")
+	default:
+		return fmt.Errorf("must be an https:// URL")
+	}
+	return nil
+}
+
+// checkURLRedirect is the CheckRedirect of the --from-url client. It applies
+// the scheme check to every redirect, so an https:// URL cannot be
+// redirected to plain HTTP.
+func (o *EditSecretOptions) checkURLRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxURLRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxURLRedirects)
+	}
+	if err := o.checkURLScheme(req.URL); err != nil {
+		return fmt.Errorf("redirect to %s %w", req.URL.Redacted(), err)
+	}
+	return nil
+}
+
+// seedFromURLs fetches each --from-url and sets the content as the key's value
+func (o *EditSecretOptions) seedFromURLs(ctx context.Context, buffer map[string]string) error {
+	for _, arg := range o.fromURLs {
+		key, rawURL, err := parseKeyValue("--from-url", arg)
+		if err != nil {
+			return err
+		}
+
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid --from-url for key %q: the URL cannot be parsed", key)
+		}
+
+		content, err := o.fetchURL(ctx, u)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s for key %q: %w", u.Redacted(), key, err)
+		}
+		buffer[key] = string(content)
+	}
+	return nil
+}
+
+// fetchURL downloads u, failing on non-2xx responses, on content larger
+// than maxURLContentSize, and on redirects rejected by checkURLRedirect
+func (o *EditSecretOptions) fetchURL(ctx context.Context, u *url.URL) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, urlFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", fmt.Sprintf("kubectl-edit-secret/%s", Version))
+
+	client := &http.Client{CheckRedirect: o.checkURLRedirect}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxURLContentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxURLContentSize {
+		return nil, fmt.Errorf("content exceeds %d bytes", maxURLContentSize)
+	}
+	return content, nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Values for --format
+const (
+	formatYAML = "yaml"
+	formatJSON = "json"
+)
+
+// jsonHeaderSentinel ends the comment header of a JSON edit buffer
+var jsonHeaderSentinel = "//" + strings.TrimPrefix(headerSentinel, "#")
+
+// renderJSONBuffer renders the edit buffer body as an indented JSON object
+// with sorted keys. HTML characters are not escaped, so values read as typed.
+func renderJSONBuffer(data map[string]string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// jsonHeader turns the '#' comment header into '//' lines, which are stripped
+// again before parsing
+func jsonHeader(header string) string {
+	lines := strings.Split(strings.TrimSuffix(header, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "//" + strings.TrimPrefix(line, "#")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// parseJSONContent parses a JSON edit buffer, dropping the '//' header up to
+// and including jsonHeaderSentinel
+func parseJSONContent(content []byte) (map[string]string, error) {
+	if i := bytes.Index(content, []byte(jsonHeaderSentinel)); i >= 0 {
+		content = content[i+len(jsonHeaderSentinel):]
+	}
+
+	result := make(map[string]string)
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return result, nil
+}
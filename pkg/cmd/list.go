@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// listSecrets prints the secrets in the namespace, or in all namespaces with
+// --all-namespaces, with their key counts
+func (o *EditSecretOptions) listSecrets(ctx context.Context) error {
+	namespace := o.namespace
+	if o.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	secrets, err := o.clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: o.selector})
+	if err != nil {
+		return fmt.Errorf("failed to list secrets: %w", o.forbiddenError("list", err))
+	}
+	if len(secrets.Items) == 0 {
+		if o.allNamespaces {
+			fmt.Fprintln(o.streams.ErrOut, "No secrets found.")
+		} else {
+			fmt.Fprintf(o.streams.ErrOut, "No secrets found in namespace %s.\n", o.namespace)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(o.streams.Out, 0, 8, 3, ' ', 0)
+	if o.allNamespaces {
+		fmt.Fprintln(w, "NAMESPACE\tNAME\tKEYS")
+	} else {
+		fmt.Fprintln(w, "NAME\tKEYS")
+	}
+	for _, s := range secrets.Items {
+		if o.allNamespaces {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", s.Namespace, s.Name, len(s.Data))
+		} else {
+			fmt.Fprintf(w, "%s\t%d\n", s.Name, len(s.Data))
+		}
+	}
+	return w.Flush()
+}
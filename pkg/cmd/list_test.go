@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// listedSecret returns a secret with the given number of keys
+func listedSecret(namespace, name string, keys int, labels map[string]string) *corev1.Secret {
+	data := make(map[string][]byte, keys)
+	for i := 0; i < keys; i++ {
+		data[string(rune('a'+i))] = []byte("x")
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Data:       data,
+	}
+}
+
+func TestRunList(t *testing.T) {
+	objects := []*corev1.Secret{
+		listedSecret("default", "db", 2, map[string]string{"app": "api"}),
+		listedSecret("default", "tls", 3, nil),
+		listedSecret("payments", "stripe-credentials", 1, map[string]string{"app": "api"}),
+	}
+
+	tests := []struct {
+		name          string
+		namespace     string
+		allNamespaces bool
+		selector      string
+		want          []string
+		wantErrOut    string
+	}{
+		{
+			name:      "namespace",
+			namespace: "default",
+			want:      []string{"NAME   KEYS", "db     2", "tls    3"},
+		},
+		{
+			name:          "all namespaces",
+			allNamespaces: true,
+			want: []string{
+				"NAMESPACE   NAME                 KEYS",
+				"default     db                   2",
+				"default     tls                  3",
+				"payments    stripe-credentials   1",
+			},
+		},
+		{
+			name:          "selector",
+			allNamespaces: true,
+			selector:      "app=api",
+			want: []string{
+				"NAMESPACE   NAME                 KEYS",
+				"default     db                   2",
+				"payments    stripe-credentials   1",
+			},
+		},
+		{
+			name:       "empty namespace",
+			namespace:  "staging",
+			wantErrOut: "No secrets found in namespace staging.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, out, errOut := newTestOptions(t, objects[0], objects[1], objects[2])
+			o.list = true
+			o.namespace = tt.namespace
+			o.allNamespaces = tt.allNamespaces
+			o.selector = tt.selector
+
+			if err := o.Run(); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if errOut.String() != tt.wantErrOut {
+				t.Errorf("stderr = %q, want %q", errOut.String(), tt.wantErrOut)
+			}
+
+			var got []string
+			if out.Len() > 0 {
+				got = strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+				for i := range got {
+					got[i] = strings.TrimRight(got[i], " ")
+				}
+				// The fake clientset lists in random order; the API server
+				// sorts by namespace and name
+				sort.Strings(got[1:])
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("stdout =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
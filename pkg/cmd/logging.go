@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"k8s.io/klog/v2"
+)
+
+// maxVerbosity is the highest -v level passed on to klog. From level 8 on,
+// client-go logs request and response bodies, which hold the secret values.
+const maxVerbosity = 7
+
+// setupLogging sends klog output, including client-go's, to stderr at the -v
+// level, capped at maxVerbosity. The command never logs secret values itself;
+// at most key names and counts.
+func (o *EditSecretOptions) setupLogging() error {
+	if o.verbosity <= 0 {
+		return nil
+	}
+
+	level := o.verbosity
+	if level > maxVerbosity {
+		fmt.Fprintf(o.streams.ErrOut, "Warning: -v=%d is lowered to %d; higher levels would log secret values\n", level, maxVerbosity)
+		level = maxVerbosity
+	}
+
+	fs := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(fs)
+	settings := [][2]string{
+		{"v", strconv.Itoa(level)},
+		{"logtostderr", "false"},
+		{"one_output", "true"},
+	}
+	for _, s := range settings {
+		if err := fs.Set(s[0], s[1]); err != nil {
+			return fmt.Errorf("failed to configure logging: %w", err)
+		}
+	}
+	klog.SetOutput(o.streams.ErrOut)
+	return nil
+}
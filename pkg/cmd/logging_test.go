@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// secretAPIServer serves GET and PUT of secret default/db, like the API server
+func secretAPIServer(t *testing.T, secret *corev1.Secret) *httptest.Server {
+	t.Helper()
+
+	var mu sync.Mutex
+	secret.APIVersion, secret.Kind = "v1", "Secret"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path != "/api/v1/namespaces/default/secrets/db" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodPut {
+			updated := &corev1.Secret{}
+			if err := json.NewDecoder(r.Body).Decode(updated); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			updated.APIVersion, updated.Kind = "v1", "Secret"
+			updated.ResourceVersion += "1"
+			secret = updated
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(secret)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// resetLogging restores klog's defaults after a test changed them
+func resetLogging(t *testing.T) {
+	t.Cleanup(func() {
+		klog.Flush()
+		fs := flag.NewFlagSet("klog", flag.ContinueOnError)
+		klog.InitFlags(fs)
+		fs.Set("v", "0")
+		fs.Set("logtostderr", "true")
+		klog.SetOutput(os.Stderr)
+	})
+}
+
+func TestVerboseLoggingOmitsValues(t *testing.T) {
+	for _, verbosity := range []int{7, 9} {
+		resetLogging(t)
+		srv := secretAPIServer(t, testSecret(map[string]string{"password": "old-s3cr3t"}))
+
+		o, _, _, errOut := newTestOptions(t)
+		o.verbosity = verbosity
+		if err := o.setupLogging(); err != nil {
+			t.Fatalf("setupLogging() error = %v", err)
+		}
+		clientset, err := newClientset(testConfigFlags(srv.URL), "", o.streams)
+		if err != nil {
+			t.Fatalf("newClientset() error = %v", err)
+		}
+		o.clientset = clientset
+		fakeEditor(o, 0, replaceValue("password: old-s3cr3t", "password: new-s3cr3t"))
+
+		if err := o.Run(); err != nil {
+			t.Fatalf("-v=%d: Run() error = %v", verbosity, err)
+		}
+		klog.Flush()
+
+		logs := errOut.String()
+		if !strings.Contains(logs, "GET "+srv.URL) || !strings.Contains(logs, "Changed keys") {
+			t.Errorf("-v=%d: stderr = %q, want request and key-name logs", verbosity, logs)
+		}
+		if verbosity > maxVerbosity && !strings.Contains(logs, "is lowered to 7") {
+			t.Errorf("-v=%d: stderr = %q, want a warning that the level is lowered", verbosity, logs)
+		}
+		for _, value := range []string{"old-s3cr3t", "new-s3cr3t"} {
+			if strings.Contains(logs, value) || strings.Contains(logs, base64.StdEncoding.EncodeToString([]byte(value))) {
+				t.Errorf("-v=%d: stderr contains the value %q:\n%s", verbosity, value, logs)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// hiddenAnnotations are left out of the --with-metadata buffer. They are
+// managed by kubectl or by this command, and keep their server values.
+var hiddenAnnotations = map[string]bool{
+	corev1.LastAppliedConfigAnnotation: true,
+	snapshotAnnotation:                 true,
+}
+
+// metadataComment is placed above the metadata section of the buffer
+const metadataComment = "Labels and annotations of the secret. Annotations managed by kubectl or\nkubectl-edit-secret are not shown and are kept as they are."
+
+// secretMetadata is the part of the secret's metadata editable with
+// --with-metadata
+type secretMetadata struct {
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// metadataBuffer is the layout of the buffer with --with-metadata
+type metadataBuffer struct {
+	Data     map[string]string `yaml:"data"`
+	Metadata secretMetadata    `yaml:"metadata"`
+}
+
+// editableMetadata returns the labels and the annotations of the secret that
+// can be edited
+func editableMetadata(secret *corev1.Secret) *secretMetadata {
+	meta := &secretMetadata{
+		Labels:      maps.Clone(secret.Labels),
+		Annotations: make(map[string]string, len(secret.Annotations)),
+	}
+	for k, v := range secret.Annotations {
+		if !hiddenAnnotations[k] {
+			meta.Annotations[k] = v
+		}
+	}
+	return meta
+}
+
+// equal reports whether both hold the same labels and annotations
+func (m *secretMetadata) equal(other *secretMetadata) bool {
+	return maps.Equal(m.Labels, other.Labels) && maps.Equal(m.Annotations, other.Annotations)
+}
+
+// applyMetadata replaces the labels and editable annotations of the secret.
+// Hidden annotations keep the values the secret has.
+func applyMetadata(secret *corev1.Secret, meta *secretMetadata) {
+	annotations := maps.Clone(meta.Annotations)
+	for k, v := range secret.Annotations {
+		if hiddenAnnotations[k] {
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[k] = v
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	labels := maps.Clone(meta.Labels)
+	if len(labels) == 0 {
+		labels = nil
+	}
+
+	secret.Labels = labels
+	secret.Annotations = annotations
+}
+
+// renderMetadataBuffer renders the buffer body for --with-metadata: the data
+// under "data" and the labels and annotations under "metadata"
+func renderMetadataBuffer(data map[string]string, binary []string, meta *secretMetadata) ([]byte, error) {
+	dataNode, err := stringMapNode(data, binary)
+	if err != nil {
+		return nil, err
+	}
+	labelsNode, err := stringMapNode(meta.Labels, nil)
+	if err != nil {
+		return nil, err
+	}
+	annotationsNode, err := stringMapNode(meta.Annotations, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	metaNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	metaNode.Content = append(metaNode.Content, scalarNode("labels"), labelsNode, scalarNode("annotations"), annotationsNode)
+
+	metaKey := scalarNode("metadata")
+	metaKey.HeadComment = metadataComment
+
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	root.Content = append(root.Content, scalarNode("data"), dataNode, metaKey, metaNode)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scalarNode returns a plain string node
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// parseMetadataContent parses a buffer edited with --with-metadata into the
+// data and the metadata
+func parseMetadataContent(content []byte) (map[string]string, *secretMetadata, error) {
+	if i := bytes.Index(content, []byte(headerSentinel)); i >= 0 {
+		content = content[i+len(headerSentinel):]
+	}
+
+	var buf metadataBuffer
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&buf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	for k := range buf.Metadata.Annotations {
+		if hiddenAnnotations[k] {
+			return nil, nil, fmt.Errorf("annotation %q is managed automatically and cannot be edited", k)
+		}
+	}
+
+	if buf.Data == nil {
+		buf.Data = make(map[string]string)
+	}
+	return buf.Data, &buf.Metadata, nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEditMetadata(t *testing.T) {
+	secret := testSecret(map[string]string{"password": "old"})
+	secret.Labels = map[string]string{"app": "db", "tier": "backend"}
+	secret.Annotations = map[string]string{
+		"owner":                            "team-a",
+		corev1.LastAppliedConfigAnnotation: `{"kind":"Secret"}`,
+	}
+
+	meta := editableMetadata(secret)
+	if _, shown := meta.Annotations[corev1.LastAppliedConfigAnnotation]; shown {
+		t.Fatal("hidden annotation is shown in the buffer")
+	}
+	body, err := renderMetadataBuffer(decodeData(secret), nil, meta)
+	if err != nil {
+		t.Fatalf("renderMetadataBuffer() error = %v", err)
+	}
+
+	// Change app, remove tier, and add env
+	content := strings.NewReplacer(
+		"app: db", "app: api",
+		"    tier: backend\n", "",
+		"  labels:\n", "  labels:\n    env: prod\n",
+	).Replace(string(body))
+
+	data, edited, err := parseMetadataContent([]byte(content))
+	if err != nil {
+		t.Fatalf("parseMetadataContent() error = %v\n%s", err, content)
+	}
+	if data["password"] != "old" {
+		t.Errorf("data = %q, want the password kept", data)
+	}
+	applyMetadata(secret, edited)
+
+	wantLabels := map[string]string{"app": "api", "env": "prod"}
+	if !reflect.DeepEqual(secret.Labels, wantLabels) {
+		t.Errorf("labels = %v, want %v", secret.Labels, wantLabels)
+	}
+	wantAnnotations := map[string]string{
+		"owner":                            "team-a",
+		corev1.LastAppliedConfigAnnotation: `{"kind":"Secret"}`,
+	}
+	if !reflect.DeepEqual(secret.Annotations, wantAnnotations) {
+		t.Errorf("annotations = %v, want %v", secret.Annotations, wantAnnotations)
+	}
+}
+
+func TestParseMetadataContentRejectsHiddenAnnotation(t *testing.T) {
+	content := "data:\n  password: old\nmetadata:\n  annotations:\n    " + corev1.LastAppliedConfigAnnotation + ": x\n"
+	if _, _, err := parseMetadataContent([]byte(content)); err == nil {
+		t.Error("parseMetadataContent() error = nil, want an error for a hidden annotation")
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// Values for --output
+const (
+	outputYAML       = "yaml"
+	outputJSON       = "json"
+	outputGoTemplate = "go-template"
+)
+
+// outputChangesJSON is the format of --output-changes
+const outputChangesJSON = "json"
+
+// changesDocument is the --output-changes summary. It lists key names only,
+// never values.
+type changesDocument struct {
+	Added   []string `json:"added"`
+	Changed []string `json:"changed"`
+	Removed []string `json:"removed"`
+}
+
+// printChanges writes the change set as a JSON object, with empty lists
+// rather than null for kinds of change that did not occur
+func printChanges(w io.Writer, cs changeSet) error {
+	doc := changesDocument{Added: []string{}, Changed: []string{}, Removed: []string{}}
+	doc.Added = append(doc.Added, cs.Added...)
+	doc.Changed = append(doc.Changed, cs.Changed...)
+	doc.Removed = append(doc.Removed, cs.Removed...)
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// outputTemplateFuncs are the helpers available in --template
+var outputTemplateFuncs = template.FuncMap{
+	"b64dec": func(s string) (string, error) {
+		decoded, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return "", fmt.Errorf("b64dec: %w", err)
+		}
+		return string(decoded), nil
+	},
+	"b64enc": func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	},
+}
+
+// parseOutputTemplate parses the --template text with outputTemplateFuncs
+func parseOutputTemplate(text string) (*template.Template, error) {
+	return template.New("output").Funcs(outputTemplateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// printSecret writes the secret as a YAML or JSON manifest, with data still
+// base64-encoded as the API stores it
+func printSecret(w io.Writer, secret *corev1.Secret, format string) error {
+	obj := secret.DeepCopy()
+	obj.APIVersion = "v1"
+	obj.Kind = "Secret"
+
+	var printer printers.ResourcePrinter
+	switch format {
+	case outputYAML:
+		printer = &printers.YAMLPrinter{}
+	case outputJSON:
+		printer = &printers.JSONPrinter{}
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+	return printer.PrintObj(obj, w)
+}
+
+// printSecretTemplate executes tmpl over the secret in its JSON form, so
+// fields are named as in the manifest, e.g. {{ index .data "password" }}
+func printSecretTemplate(w io.Writer, secret *corev1.Secret, tmpl *template.Template) error {
+	obj := secret.DeepCopy()
+	obj.APIVersion = "v1"
+	obj.Kind = "Secret"
+
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to encode secret: %w", err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return fmt.Errorf("failed to encode secret: %w", err)
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("secret %s was saved, but --template failed: %w", secret.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintSecretTemplate(t *testing.T) {
+	secret := testSecret(map[string]string{"password": "s3cr3t", "user": "admin"})
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{"raw data", `{{ index .data "password" }}`, "czNjcjN0", ""},
+		{"b64dec", `{{ index .data "password" | b64dec }}`, "s3cr3t", ""},
+		{"b64enc", `{{ "admin" | b64enc }}`, "YWRtaW4=", ""},
+		{"metadata", `{{ .kind }}/{{ .metadata.name }} rv={{ .metadata.resourceVersion }}`, "Secret/db rv=1", ""},
+		{"range", `{{ range $k, $v := .data }}{{ $k }}={{ b64dec $v }};{{ end }}`, "password=s3cr3t;user=admin;", ""},
+		{"missing field", `{{ .spec.replicas }}`, "", "--template failed"},
+		{"not base64", `{{ "%%%" | b64dec }}`, "", "b64dec"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseOutputTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("parseOutputTemplate() error = %v", err)
+			}
+
+			var out bytes.Buffer
+			err = printSecretTemplate(&out, secret, tmpl)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("printSecretTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("printSecretTemplate() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunOutputChanges(t *testing.T) {
+	o, _, out, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "user": "admin", "legacy": "x"}))
+	o.outputChanges = outputChangesJSON
+	fakeEditor(o, 0, func(_ int, content string) string {
+		content = strings.Replace(content, "password: old", "password: new", 1)
+		content = strings.Replace(content, "legacy: x\n", "", 1)
+		return content + "token: t1\n"
+	})
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := `{"added":["token"],"changed":["password"],"removed":["legacy"]}` + "\n"
+	if out.String() != want {
+		t.Errorf("stdout = %q, want %q", out.String(), want)
+	}
+}
+
+func TestPrintChangesEmptyLists(t *testing.T) {
+	var out bytes.Buffer
+	if err := printChanges(&out, changeSet{Changed: []string{"password"}}); err != nil {
+		t.Fatalf("printChanges() error = %v", err)
+	}
+	if want := `{"added":[],"changed":["password"],"removed":[]}` + "\n"; out.String() != want {
+		t.Errorf("printChanges() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestRunSuccessTemplate(t *testing.T) {
+	o, _, out, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "legacy": "x"}))
+	o.successTemplate = "{{.Namespace}}/{{.Name}}: {{.AddedCount}} added, {{.ChangedCount}} changed, {{.RemovedCount}} removed\n"
+	fakeEditor(o, 0, func(_ int, content string) string {
+		content = strings.Replace(content, "password: old", "password: new", 1)
+		content = strings.Replace(content, "legacy: x\n", "", 1)
+		return content + "token: t1\nuser: admin\n"
+	})
+
+	if err := o.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := "default/db: 2 added, 1 changed, 1 removed\n"; out.String() != want {
+		t.Errorf("stdout = %q, want %q", out.String(), want)
+	}
+}
+
+func TestValidateSuccessTemplate(t *testing.T) {
+	o, _, _, _ := newTestOptions(t)
+	o.successTemplate = "edited {{.Name"
+
+	err := o.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid --success-template") {
+		t.Errorf("Validate() error = %v, want the template parse error", err)
+	}
+}
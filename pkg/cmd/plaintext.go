@@ -0,0 +1,42 @@
+package cmd
+
+import "fmt"
+
+// plaintextMode returns the flag of the mode that prints decoded values to
+// stdout, or an empty string if no such mode is set
+func (o *EditSecretOptions) plaintextMode() string {
+	switch {
+	case o.get:
+		return "--get"
+	case o.exportEnv:
+		return "--export-env"
+	case o.renderBuffer:
+		return "--render-buffer"
+	case o.showPrevious:
+		return "--show-previous"
+	case o.diffWith != "":
+		return "--diff-with"
+	case o.diff:
+		return "--diff"
+	case o.output == outputGoTemplate:
+		return "--template"
+	case o.previewEnc:
+		return "--preview-encoded"
+	}
+	return ""
+}
+
+// checkPlaintextOutput refuses to print decoded values when the stream they go
+// to is not a terminal, where they could end up in CI logs, unless --force is
+// set. --preview-encoded prints to stderr, every other mode to stdout.
+func (o *EditSecretOptions) checkPlaintextOutput() error {
+	mode := o.plaintextMode()
+	out, name := o.streams.Out, "stdout"
+	if mode == "--preview-encoded" {
+		out, name = o.streams.ErrOut, "stderr"
+	}
+	if mode == "" || o.force || isTerminal(out) {
+		return nil
+	}
+	return fmt.Errorf("%s prints decoded secret values, and %s is not a terminal, so they could end up in logs; pass --force to print them anyway", mode, name)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPlaintextOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(o *EditSecretOptions)
+		wantErr string
+	}{
+		{"edit", func(o *EditSecretOptions) {}, ""},
+		{"get", func(o *EditSecretOptions) { o.get = true }, "--get prints decoded secret values"},
+		{"diff", func(o *EditSecretOptions) { o.diff = true }, "--diff prints decoded secret values"},
+		{"diff-with", func(o *EditSecretOptions) { o.diffWith = "other" }, "--diff-with prints decoded secret values"},
+		{"diff with --force", func(o *EditSecretOptions) { o.diff, o.force = true, true }, ""},
+		{"diff-with with --force", func(o *EditSecretOptions) { o.diffWith, o.force = "other", true }, ""},
+		{"template", func(o *EditSecretOptions) { o.output = outputGoTemplate }, "--template prints decoded secret values"},
+		{"template with --force", func(o *EditSecretOptions) { o.output, o.force = outputGoTemplate, true }, ""},
+		{"output yaml", func(o *EditSecretOptions) { o.output = outputYAML }, ""},
+		{"preview-encoded", func(o *EditSecretOptions) { o.previewEnc = true }, "--preview-encoded prints decoded secret values, and stderr is not a terminal"},
+		{"preview-encoded with --force", func(o *EditSecretOptions) { o.previewEnc, o.force = true, true }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// stdout and stderr are bytes.Buffers, so never a terminal
+			o, _, _, _ := newTestOptions(t)
+			tt.set(o)
+
+			err := o.checkPlaintextOutput()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("checkPlaintextOutput() error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("checkPlaintextOutput() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
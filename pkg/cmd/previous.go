@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// lastAppliedData returns the decoded data of the secret's last-applied
+// configuration, and false if the secret has none
+func lastAppliedData(secret *corev1.Secret) (map[string]string, bool, error) {
+	raw, ok := secret.Annotations[corev1.LastAppliedConfigAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+
+	var applied corev1.Secret
+	if err := json.Unmarshal([]byte(raw), &applied); err != nil {
+		return nil, true, fmt.Errorf("failed to parse %s annotation: %w", corev1.LastAppliedConfigAnnotation, err)
+	}
+
+	data := make(map[string]string, len(applied.Data)+len(applied.StringData))
+	for k, v := range applied.Data {
+		data[k] = string(v)
+	}
+	for k, v := range applied.StringData {
+		data[k] = v
+	}
+	return data, true, nil
+}
+
+// printPrevious prints how the live values differ from the last-applied
+// configuration. live holds the decoded values in scope; with KEY, --key, or
+// a pattern, only those keys are compared. The previous values are decoded
+// the same way as live.
+func (o *EditSecretOptions) printPrevious(secret *corev1.Secret, live map[string]string) error {
+	previous, ok, err := lastAppliedData(secret)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Fprintf(o.streams.Out, "Secret %s has no %s annotation, so there is nothing to compare with.\n", o.secretName, corev1.LastAppliedConfigAnnotation)
+		return nil
+	}
+
+	if o.key != "" || len(o.keys) > 0 || o.keyPattern != "" {
+		for k := range previous {
+			if _, inScope := live[k]; !inScope {
+				delete(previous, k)
+			}
+		}
+	}
+	if o.decodeFilter != "" {
+		if err := o.decodeFilterValues(previous); err != nil {
+			return err
+		}
+	}
+	if o.decodeBase64 {
+		decodeBase64Values(previous)
+	}
+
+	cs := computeChanges(previous, live)
+	if len(cs.Added)+len(cs.Changed)+len(cs.Removed) == 0 {
+		fmt.Fprintf(o.streams.Out, "Secret %s matches its last-applied configuration.\n", o.secretName)
+		return nil
+	}
+	renderDiff(o.streams.Out, cs, previous, live, !o.noColor && isTerminal(o.streams.Out))
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// withLastApplied records data as the secret's last-applied configuration
+func withLastApplied(t *testing.T, secret *corev1.Secret, data map[string]string) *corev1.Secret {
+	t.Helper()
+
+	applied := testSecret(data)
+	applied.ResourceVersion = ""
+	raw, err := json.Marshal(applied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret.Annotations = map[string]string{corev1.LastAppliedConfigAnnotation: string(raw)}
+	return secret
+}
+
+func TestRunShowPrevious(t *testing.T) {
+	tests := []struct {
+		name         string
+		secret       func(t *testing.T) *corev1.Secret
+		decodeBase64 bool
+		want         string
+	}{
+		{
+			name: "no annotation",
+			secret: func(t *testing.T) *corev1.Secret {
+				return testSecret(map[string]string{"password": "old"})
+			},
+			want: "Secret db has no " + corev1.LastAppliedConfigAnnotation + " annotation",
+		},
+		{
+			name: "unchanged",
+			secret: func(t *testing.T) *corev1.Secret {
+				return withLastApplied(t, testSecret(map[string]string{"password": "old"}), map[string]string{"password": "old"})
+			},
+			want: "Secret db matches its last-applied configuration.",
+		},
+		{
+			name: "changed",
+			secret: func(t *testing.T) *corev1.Secret {
+				return withLastApplied(t, testSecret(map[string]string{"password": "new"}), map[string]string{"password": "old"})
+			},
+			want: "-old",
+		},
+		{
+			name: "unchanged with --decode-base64-values",
+			secret: func(t *testing.T) *corev1.Secret {
+				return withLastApplied(t, testSecret(map[string]string{"token": "c2VjcmV0"}), map[string]string{"token": "c2VjcmV0"})
+			},
+			decodeBase64: true,
+			want:         "Secret db matches its last-applied configuration.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, out, _ := newTestOptions(t, tt.secret(t))
+			o.showPrevious = true
+			o.force = true
+			o.decodeBase64 = tt.decodeBase64
+
+			if err := o.Run(); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// isTerminal reports whether the given stream is attached to a terminal
+func isTerminal(stream interface{}) bool {
+	f, ok := stream.(*os.File)
+	if !ok {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// readLine reads a single line of user input from the input stream,
+// without the trailing newline
+func (o *EditSecretOptions) readLine() (string, error) {
+	if o.in == nil {
+		o.in = bufio.NewReader(o.promptStdin())
+	}
+
+	line, err := o.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// confirm asks a yes/no question and reports whether the user answered yes
+func (o *EditSecretOptions) confirm(question string) (bool, error) {
+	fmt.Fprintf(o.streams.ErrOut, "%s [y/N]: ", question)
+
+	answer, err := o.readLine()
+	if err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// confirmWord asks the user to type expected to proceed, as a stronger guard
+// than a y/N question for high-risk operations
+func (o *EditSecretOptions) confirmWord(action, expected string) (bool, error) {
+	fmt.Fprintf(o.streams.ErrOut, "Type %q to %s: ", expected, action)
+
+	answer, err := o.readLine()
+	if err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.TrimSpace(answer) == expected, nil
+}
+
+// choose asks the user to pick one of options by number
+func (o *EditSecretOptions) choose(title string, options []string) (string, error) {
+	for {
+		fmt.Fprintln(o.streams.ErrOut, title)
+		for i, opt := range options {
+			fmt.Fprintf(o.streams.ErrOut, "  %d) %s\n", i+1, opt)
+		}
+		fmt.Fprint(o.streams.ErrOut, "> ")
+
+		answer, err := o.readLine()
+		if err != nil {
+			return "", fmt.Errorf("failed to read choice: %w", err)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(answer))
+		if err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		fmt.Fprintf(o.streams.ErrOut, "Enter a number between 1 and %d\n", len(options))
+	}
+}
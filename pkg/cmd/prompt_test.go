@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunConfirm(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  string
+		applied bool
+	}{
+		{"yes", "y\n", true},
+		{"yes in full", "Yes\n", true},
+		{"default", "\n", false},
+		{"no", "n\n", false},
+		{"end of input", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, clientset, out, errOut := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+			o.confirmApply = true
+			o.streams.In = strings.NewReader(tt.answer)
+			fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+			if err := o.Run(); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !strings.Contains(errOut.String(), "Apply 0 added, 1 changed, and 0 removed key(s) to secret db? [y/N]: ") {
+				t.Errorf("stderr = %q, want the confirmation question", errOut.String())
+			}
+
+			want, wantOut := "old", "Aborted.\n"
+			if tt.applied {
+				want, wantOut = "new", "secret/db edited\n"
+			}
+			if got := storedData(t, clientset)["password"]; got != want {
+				t.Errorf("stored password = %q, want %q", got, want)
+			}
+			if out.String() != wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recordLastApplied sets the last-applied-configuration annotation to the
+// secret's new configuration, as kubectl apply does, so later applies diff
+// against the edited values. The annotation holds the encoded data, so it is
+// as sensitive as the secret itself.
+func recordLastApplied(secret *corev1.Secret) error {
+	annotations := make(map[string]string, len(secret.Annotations))
+	for k, v := range secret.Annotations {
+		if k != corev1.LastAppliedConfigAnnotation {
+			annotations[k] = v
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	applied := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secret.Name,
+			Namespace:   secret.Namespace,
+			Labels:      secret.Labels,
+			Annotations: annotations,
+		},
+		Type:      secret.Type,
+		Immutable: secret.Immutable,
+		Data:      secret.Data,
+	}
+
+	encoded, err := json.Marshal(applied)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", corev1.LastAppliedConfigAnnotation, err)
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[corev1.LastAppliedConfigAnnotation] = string(encoded)
+	return nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRunRecord(t *testing.T) {
+	live := withLastApplied(t, testSecret(map[string]string{"password": "old", "user": "admin"}), map[string]string{"password": "old"})
+	live.Annotations["team"] = "payments"
+	o, clientset, _, _ := newTestOptions(t, live)
+	o.record = true
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	obj, err := clientset.Tracker().Get(corev1.SchemeGroupVersion.WithResource("secrets"), "default", "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := obj.(*corev1.Secret)
+
+	var applied corev1.Secret
+	if err := json.Unmarshal([]byte(stored.Annotations[corev1.LastAppliedConfigAnnotation]), &applied); err != nil {
+		t.Fatalf("invalid %s annotation: %v", corev1.LastAppliedConfigAnnotation, err)
+	}
+	if applied.Kind != "Secret" || applied.APIVersion != "v1" || applied.Name != "db" || applied.Namespace != "default" {
+		t.Errorf("recorded object = %s %s %s/%s, want v1 Secret default/db", applied.APIVersion, applied.Kind, applied.Namespace, applied.Name)
+	}
+	if want := map[string]string{"password": "new", "user": "admin"}; !reflect.DeepEqual(decodeData(&applied), want) {
+		t.Errorf("recorded data = %q, want %q", decodeData(&applied), want)
+	}
+	if want := map[string]string{"team": "payments"}; !reflect.DeepEqual(applied.Annotations, want) {
+		t.Errorf("recorded annotations = %q, want %q without the last-applied annotation itself", applied.Annotations, want)
+	}
+	if applied.ResourceVersion != "" || applied.UID != "" {
+		t.Errorf("recorded object has server fields: resourceVersion %q, uid %q", applied.ResourceVersion, applied.UID)
+	}
+}
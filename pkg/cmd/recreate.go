@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recreateSecret replaces the secret by deleting it and creating edited in its
+// place, keeping labels, annotations, type, and immutability. This is the only
+// way to change an immutable secret. Between the delete and the create the
+// secret does not exist; if the create fails, the original is created again.
+func (o *EditSecretOptions) recreateSecret(ctx context.Context, original, edited *corev1.Secret) (*corev1.Secret, error) {
+	if len(original.Finalizers) > 0 {
+		return nil, fmt.Errorf("secret %s has finalizers, so it would not be deleted right away; not recreating it", o.secretName)
+	}
+
+	proceed, err := o.confirmWord("delete and recreate secret "+o.secretName, o.secretName)
+	if err != nil {
+		return nil, err
+	}
+	if !proceed {
+		return nil, fmt.Errorf("aborted, secret %s was not recreated", o.secretName)
+	}
+
+	ctx, cancel := o.withTimeout(ctx)
+	defer cancel()
+
+	secrets := o.clientset.CoreV1().Secrets(o.namespace)
+
+	// The precondition fails the delete if the secret changed since it was read
+	rv := original.ResourceVersion
+	err = secrets.Delete(ctx, o.secretName, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: &rv}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete secret %s, nothing was changed: %w", o.secretName, err)
+	}
+
+	created, err := secrets.Create(ctx, freshCopy(edited), metav1.CreateOptions{})
+	if err == nil {
+		return created, nil
+	}
+
+	// The restore gets a fresh timeout, as the create may have failed by
+	// running out of it
+	restoreCtx, cancelRestore := o.withTimeout(context.WithoutCancel(ctx))
+	defer cancelRestore()
+	if _, restoreErr := secrets.Create(restoreCtx, freshCopy(original), metav1.CreateOptions{}); restoreErr != nil {
+		return nil, fmt.Errorf("failed to create the edited secret %s: %v; restoring the original also failed, the secret no longer exists: %w", o.secretName, err, restoreErr)
+	}
+	return nil, fmt.Errorf("failed to create the edited secret %s, the original was restored: %w", o.secretName, err)
+}
+
+// freshCopy returns a copy of the secret without the fields the server sets,
+// so it can be created again
+func freshCopy(secret *corev1.Secret) *corev1.Secret {
+	s := secret.DeepCopy()
+	s.ResourceVersion = ""
+	s.UID = ""
+	s.CreationTimestamp = metav1.Time{}
+	s.DeletionTimestamp = nil
+	s.Generation = 0
+	s.ManagedFields = nil
+	return s
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// immutableSecret returns an immutable secret default/db with a label
+func immutableSecret() *corev1.Secret {
+	secret := testSecret(map[string]string{"password": "old"})
+	immutable := true
+	secret.Immutable = &immutable
+	secret.Labels = map[string]string{"app": "db"}
+	return secret
+}
+
+func TestRecreateSecret(t *testing.T) {
+	original := immutableSecret()
+	o, clientset, _, _ := newTestOptions(t, original)
+	o.streams.In = strings.NewReader("db\n")
+
+	edited := original.DeepCopy()
+	edited.Data["password"] = []byte("new")
+	if _, err := o.recreateSecret(context.Background(), original, edited); err != nil {
+		t.Fatalf("recreateSecret() error = %v", err)
+	}
+	if got := storedData(t, clientset)["password"]; got != "new" {
+		t.Errorf("stored password = %q, want %q", got, "new")
+	}
+
+	var verbs []string
+	for _, action := range clientset.Actions() {
+		verbs = append(verbs, action.GetVerb())
+	}
+	if got := strings.Join(verbs, ","); got != "delete,create" {
+		t.Errorf("actions = %s, want delete,create", got)
+	}
+}
+
+func TestRecreateSecretRestoresOnCreateFailure(t *testing.T) {
+	original := immutableSecret()
+	o, clientset, _, _ := newTestOptions(t, original)
+	o.streams.In = strings.NewReader("db\n")
+
+	failed := false
+	clientset.PrependReactor("create", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if failed {
+			return false, nil, nil
+		}
+		failed = true
+		return true, nil, errors.New("admission webhook denied the request")
+	})
+
+	edited := original.DeepCopy()
+	edited.Data["password"] = []byte("new")
+	_, err := o.recreateSecret(context.Background(), original, edited)
+	if err == nil || !strings.Contains(err.Error(), "the original was restored") {
+		t.Fatalf("recreateSecret() error = %v, want the restore to be reported", err)
+	}
+
+	obj, err := clientset.Tracker().Get(corev1.SchemeGroupVersion.WithResource("secrets"), "default", "db")
+	if err != nil {
+		t.Fatalf("secret is gone after the restore: %v", err)
+	}
+	restored := obj.(*corev1.Secret)
+	if string(restored.Data["password"]) != "old" || restored.Labels["app"] != "db" || restored.Immutable == nil || !*restored.Immutable {
+		t.Errorf("restored secret = %+v, want the original", restored)
+	}
+}
+
+func TestRecreateSecretNeedsTheName(t *testing.T) {
+	original := immutableSecret()
+	o, clientset, _, _ := newTestOptions(t, original)
+	o.streams.In = strings.NewReader("yes\n")
+
+	if _, err := o.recreateSecret(context.Background(), original, original.DeepCopy()); err == nil {
+		t.Fatal("recreateSecret() error = nil, want an abort")
+	}
+	if n := len(clientset.Actions()); n != 0 {
+		t.Errorf("%d API calls made after a wrong confirmation", n)
+	}
+}
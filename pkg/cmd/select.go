@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore matches pattern against s as a case-insensitive subsequence.
+// It returns false if pattern does not match, otherwise a score that is
+// higher for consecutive runs and matches at word boundaries.
+func fuzzyScore(pattern, s string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	r := []rune(strings.ToLower(s))
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	score, pi, prev := 0, 0, -2
+	for i := 0; i < len(r) && pi < len(p); i++ {
+		if r[i] != p[pi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(r[i-1]) && !unicode.IsDigit(r[i-1]) {
+			score += 3
+		}
+		prev = i
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+// fuzzyFilter returns the candidates matching pattern, best matches first
+func fuzzyFilter(pattern string, candidates []string) []string {
+	type match struct {
+		value string
+		score int
+	}
+
+	matches := make([]match, 0, len(candidates))
+	for _, c := range candidates {
+		if score, ok := fuzzyScore(pattern, c); ok {
+			matches = append(matches, match{c, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.value
+	}
+	return result
+}
+
+// selectKeys lets the user pick keys interactively. Typing text filters the
+// list with fuzzy matching, numbers toggle the shown entries, and an empty
+// line finishes the selection.
+func (o *EditSecretOptions) selectKeys(title string, keys []string) ([]string, error) {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+
+	selected := make(map[string]bool)
+	shown := sorted
+	for {
+		fmt.Fprintf(o.streams.ErrOut, "%s (text filters, '*' shows all, numbers toggle, 'a' toggles all shown, empty line continues):\n", title)
+		for i, k := range shown {
+			mark := " "
+			if selected[k] {
+				mark = "x"
+			}
+			fmt.Fprintf(o.streams.ErrOut, "  [%s] %d) %s\n", mark, i+1, k)
+		}
+		fmt.Fprint(o.streams.ErrOut, "> ")
+
+		line, err := o.readLine()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read selection: %w", err)
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "":
+			result := make([]string, 0, len(selected))
+			for _, k := range sorted {
+				if selected[k] {
+					result = append(result, k)
+				}
+			}
+			if len(result) == 0 {
+				return nil, fmt.Errorf("no keys selected")
+			}
+			return result, nil
+		case line == "*":
+			shown = sorted
+		case line == "a":
+			for _, k := range shown {
+				selected[k] = !selected[k]
+			}
+		case isIndexList(line):
+			for _, field := range strings.FieldsFunc(line, isListSeparator) {
+				n, _ := strconv.Atoi(field)
+				if n < 1 || n > len(shown) {
+					fmt.Fprintf(o.streams.ErrOut, "No entry %d\n", n)
+					continue
+				}
+				selected[shown[n-1]] = !selected[shown[n-1]]
+			}
+		default:
+			shown = fuzzyFilter(line, sorted)
+		}
+	}
+}
+
+// isIndexList reports whether s is a list of numbers separated by spaces or commas
+func isIndexList(s string) bool {
+	fields := strings.FieldsFunc(s, isListSeparator)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, f := range fields {
+		if _, err := strconv.Atoi(f); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isListSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunFzfSelection(t *testing.T) {
+	o, clientset, _, errOut := newTestOptions(t, testSecret(map[string]string{"api-key": "k1", "password": "old", "user": "admin"}))
+	o.fzf = true
+	o.terminal = func(interface{}) bool { return true }
+	// Filter down to password, pick it, then finish the selection
+	o.streams.In = strings.NewReader("pass\n1\n\n")
+	var buffer string
+	fakeEditor(o, 0, func(_ int, content string) string {
+		buffer = content
+		return strings.Replace(content, "password: old", "password: new", 1)
+	})
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !strings.Contains(errOut.String(), "[x] 1) password") {
+		t.Errorf("stderr = %q, want password marked as selected", errOut.String())
+	}
+	if strings.Contains(buffer, "api-key") || strings.Contains(buffer, "user") {
+		t.Errorf("buffer =\n%s\nwant only the selected key", buffer)
+	}
+	want := map[string]string{"api-key": "k1", "password": "new", "user": "admin"}
+	if got := storedData(t, clientset); !reflect.DeepEqual(got, want) {
+		t.Errorf("stored data = %q, want %q", got, want)
+	}
+}
+
+func TestRunFzfNothingSelected(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "user": "admin"}))
+	o.fzf = true
+	o.terminal = func(interface{}) bool { return true }
+	// Toggling an entry twice leaves it unselected
+	o.streams.In = strings.NewReader("1\n1\n\n")
+
+	err := o.Run()
+	if err == nil || !strings.Contains(err.Error(), "no keys selected") {
+		t.Fatalf("Run() error = %v, want %q", err, "no keys selected")
+	}
+	if got := storedData(t, clientset)["password"]; got != "old" {
+		t.Errorf("stored password = %q, want it untouched", got)
+	}
+}
+
+func TestRunInteractiveDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		question string
+		want     map[string]string
+	}{
+		{"confirmed", "1,3\n\ny\n", "Delete 2 key(s) (legacy, token)", map[string]string{"password": "old"}},
+		{"declined", "1,3\n\nn\n", "Delete 2 key(s) (legacy, token)", map[string]string{"legacy": "l", "password": "old", "token": "t"}},
+		{"toggle all shown", "leg\na\n*\n\ny\n", "Delete 1 key(s) (legacy)", map[string]string{"password": "old", "token": "t"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, clientset, _, errOut := newTestOptions(t, testSecret(map[string]string{"legacy": "l", "password": "old", "token": "t"}))
+			o.interDelete = true
+			o.terminal = func(interface{}) bool { return true }
+			o.streams.In = strings.NewReader(tt.input)
+
+			if err := o.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if err := o.Run(); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !strings.Contains(errOut.String(), tt.question+" from secret db? [y/N]: ") {
+				t.Errorf("stderr = %q, want %q", errOut.String(), tt.question)
+			}
+			if got := storedData(t, clientset); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stored data = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateInteractiveDeleteNeedsTerminal(t *testing.T) {
+	o, _, _, _ := newTestOptions(t)
+	o.interDelete = true
+
+	err := o.Validate()
+	if err == nil || !strings.Contains(err.Error(), "requires an interactive terminal") {
+		t.Errorf("Validate() error = %v, want the terminal error", err)
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resolveServiceAccountSecret sets the secret name from the secrets and
+// image pull secrets referenced by the --from-serviceaccount ServiceAccount,
+// asking the user to choose if there are several. ctx is only used before the
+// prompt.
+func (o *EditSecretOptions) resolveServiceAccountSecret(ctx context.Context) error {
+	sa, err := o.clientset.CoreV1().ServiceAccounts(o.namespace).Get(ctx, o.fromServiceAccount, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get serviceaccount %s: %w", o.fromServiceAccount, err)
+	}
+
+	seen := make(map[string]bool)
+	names := make([]string, 0, len(sa.Secrets)+len(sa.ImagePullSecrets))
+	for _, ref := range sa.Secrets {
+		if ref.Name != "" && !seen[ref.Name] {
+			seen[ref.Name] = true
+			names = append(names, ref.Name)
+		}
+	}
+	for _, ref := range sa.ImagePullSecrets {
+		if ref.Name != "" && !seen[ref.Name] {
+			seen[ref.Name] = true
+			names = append(names, ref.Name)
+		}
+	}
+
+	switch {
+	case len(names) == 0:
+		return fmt.Errorf("serviceaccount %s does not reference any secrets", o.fromServiceAccount)
+	case len(names) == 1:
+		o.secretName = names[0]
+	case !o.terminal(o.streams.In):
+		return fmt.Errorf("serviceaccount %s references several secrets (%s); pass one as SECRET_NAME instead", o.fromServiceAccount, strings.Join(names, ", "))
+	default:
+		name, err := o.choose(fmt.Sprintf("Serviceaccount %s references several secrets:", o.fromServiceAccount), names)
+		if err != nil {
+			return err
+		}
+		o.secretName = name
+	}
+	return nil
+}
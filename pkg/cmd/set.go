@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// setsValues reports whether --set, --from-file, --rename, --delete, or
+// --import-env change values directly, in which case the editor is skipped
+func (o *EditSecretOptions) setsValues() bool {
+	return len(o.sets) > 0 || len(o.fromFiles) > 0 || len(o.renames) > 0 || len(o.deletes) > 0 || o.importEnvPath != ""
+}
+
+// validateSets checks the --set, --from-file, --rename, --delete, and
+// --import-env arguments
+func (o *EditSecretOptions) validateSets() error {
+	switch o.importMode {
+	case importMerge, importReplace:
+	default:
+		return fmt.Errorf("invalid --import-mode %q, must be one of: merge, replace", o.importMode)
+	}
+
+	if !o.setsValues() {
+		return nil
+	}
+	if o.key != "" || o.keyPattern != "" || len(o.keys) > 0 {
+		return fmt.Errorf("--set, --from-file, --rename, --delete, and --import-env cannot be combined with KEY or --key")
+	}
+	if o.interDelete {
+		return fmt.Errorf("--set, --from-file, --rename, --delete, and --import-env cannot be combined with --interactive-delete")
+	}
+
+	for _, arg := range o.renames {
+		oldKey, newKey, err := parseKeyValue("--rename", arg)
+		if err != nil {
+			return err
+		}
+		if newKey == "" || newKey == oldKey {
+			return fmt.Errorf("invalid --rename %q, expected old=new with a different new name", arg)
+		}
+	}
+
+	deleted := make(map[string]bool, len(o.deletes))
+	for _, k := range o.deletes {
+		if k == "" {
+			return fmt.Errorf("--delete requires a key name")
+		}
+		deleted[k] = true
+	}
+
+	fileKeys := make(map[string]bool, len(o.fromFiles))
+	for _, arg := range o.fromFiles {
+		key, path, err := parseKeyValue("--from-file", arg)
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			return fmt.Errorf("invalid --from-file %q, expected key=path", arg)
+		}
+		if deleted[key] {
+			return fmt.Errorf("key %q is given with both --delete and --from-file", key)
+		}
+		fileKeys[key] = true
+	}
+	for _, arg := range o.sets {
+		key, _, err := parseKeyValue("--set", arg)
+		if err != nil {
+			return err
+		}
+		if fileKeys[key] {
+			return fmt.Errorf("key %q is given with both --set and --from-file", key)
+		}
+		if deleted[key] {
+			return fmt.Errorf("key %q is given with both --delete and --set", key)
+		}
+	}
+	return nil
+}
+
+// seedFromSets applies each --rename and --delete to the buffer, then the
+// --import-env file, then writes each --from-file and --set value into it.
+// File contents are kept byte for byte. Later flags win over earlier ones for
+// the same key.
+func (o *EditSecretOptions) seedFromSets(buffer map[string]string) error {
+	for _, arg := range o.renames {
+		oldKey, newKey, err := parseKeyValue("--rename", arg)
+		if err != nil {
+			return err
+		}
+		value, ok := buffer[oldKey]
+		if !ok {
+			return fmt.Errorf("cannot rename key %q: it does not exist. Available keys: %s", oldKey, strings.Join(sortedStringKeys(buffer), ", "))
+		}
+		if _, exists := buffer[newKey]; exists && !o.overwrite {
+			return fmt.Errorf("cannot rename key %q to %q: %q already exists (use --overwrite to replace it)", oldKey, newKey, newKey)
+		}
+		buffer[newKey] = value
+		delete(buffer, oldKey)
+	}
+
+	if len(o.deletes) > 0 {
+		if err := o.deleteKeys(buffer); err != nil {
+			return err
+		}
+	}
+
+	if o.importEnvPath != "" {
+		if err := o.importEnv(buffer); err != nil {
+			return err
+		}
+	}
+
+	for _, arg := range o.fromFiles {
+		key, path, err := parseKeyValue("--from-file", arg)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read --from-file for key %q: %w", key, err)
+		}
+		buffer[key] = string(content)
+	}
+
+	for _, arg := range o.sets {
+		key, value, err := parseKeyValue("--set", arg)
+		if err != nil {
+			return err
+		}
+		buffer[key] = value
+	}
+
+	removes := len(o.deletes) > 0 || (o.importEnvPath != "" && o.importMode == importReplace)
+	if removes && len(buffer) == 0 && !o.allowEmpty {
+		return fmt.Errorf("this would leave secret %s with no data; pass --allow-empty if that is intended", o.secretName)
+	}
+	return nil
+}
+
+// sortedStringKeys returns the keys of data in sorted order
+func sortedStringKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// deleteKeys removes the --delete keys from the buffer. Missing keys are
+// reported, and it fails if none of them exist.
+func (o *EditSecretOptions) deleteKeys(buffer map[string]string) error {
+	var missing []string
+	for _, k := range o.deletes {
+		if _, ok := buffer[k]; !ok {
+			missing = append(missing, k)
+			continue
+		}
+		delete(buffer, k)
+	}
+
+	if len(missing) == len(o.deletes) {
+		return fmt.Errorf("none of the keys to delete exist in secret %s. Available keys: %s", o.secretName, strings.Join(sortedStringKeys(buffer), ", "))
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(o.streams.ErrOut, "Warning: keys not found, skipping: %s\n", strings.Join(missing, ", "))
+	}
+	return nil
+}
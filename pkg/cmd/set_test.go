@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRunFromFileBinary(t *testing.T) {
+	content := []byte{0x00, 0xff, 0xfe, '\r', '\n', 0x80, 'k', 'e', 'y'}
+	path := filepath.Join(t.TempDir(), "keystore.p12")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	o.fromFiles = []string{"keystore=" + path}
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	obj, err := clientset.Tracker().Get(corev1.SchemeGroupVersion.WithResource("secrets"), "default", "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := obj.(*corev1.Secret).Data["keystore"]; !bytes.Equal(got, content) {
+		t.Errorf("stored keystore = %x, want %x", got, content)
+	}
+}
+
+func TestRunFromFileMissing(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	o.fromFiles = []string{"keystore=" + filepath.Join(t.TempDir(), "missing.p12")}
+
+	err := o.Run()
+	if err == nil || !strings.Contains(err.Error(), `failed to read --from-file for key "keystore"`) {
+		t.Fatalf("Run() error = %v, want a read error naming the key", err)
+	}
+	if _, ok := storedData(t, clientset)["keystore"]; ok {
+		t.Error("keystore was stored despite the error")
+	}
+}
+
+func TestSeedFromSetsRename(t *testing.T) {
+	tests := []struct {
+		name      string
+		renames   []string
+		overwrite bool
+		want      map[string]string
+		wantErr   string
+	}{
+		{"rename", []string{"pass=password"}, false, map[string]string{"password": "old", "user": "admin"}, ""},
+		{"collision", []string{"pass=user"}, false, nil, `cannot rename key "pass" to "user": "user" already exists (use --overwrite to replace it)`},
+		{"collision with --overwrite", []string{"pass=user"}, true, map[string]string{"user": "old"}, ""},
+		{"missing key", []string{"token=api-token"}, false, nil, `cannot rename key "token": it does not exist. Available keys: pass, user`},
+		{"chained", []string{"pass=tmp", "tmp=password"}, false, map[string]string{"password": "old", "user": "admin"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, _, _ := newTestOptions(t)
+			o.renames = tt.renames
+			o.overwrite = tt.overwrite
+			buffer := map[string]string{"pass": "old", "user": "admin"}
+
+			err := o.seedFromSets(buffer)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("seedFromSets() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("seedFromSets() error = %v", err)
+			}
+			if !reflect.DeepEqual(buffer, tt.want) {
+				t.Errorf("buffer = %q, want %q", buffer, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		deletes    []string
+		allowEmpty bool
+		want       map[string]string
+		wantErr    string
+		wantWarn   string
+	}{
+		{"several keys", []string{"password", "token"}, false, map[string]string{"user": "admin"}, "", ""},
+		{"missing key skipped", []string{"password", "nope"}, false, map[string]string{"user": "admin", "token": "t1"}, "", "keys not found, skipping: nope"},
+		{"none exist", []string{"nope"}, false, nil, "none of the keys to delete exist in secret db", ""},
+		{"every key", []string{"password", "token", "user"}, false, nil, "pass --allow-empty if that is intended", ""},
+		{"every key with --allow-empty", []string{"password", "token", "user"}, true, map[string]string{}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, clientset, _, errOut := newTestOptions(t, testSecret(map[string]string{"password": "old", "token": "t1", "user": "admin"}))
+			o.deletes = tt.deletes
+			o.allowEmpty = tt.allowEmpty
+
+			err := o.Run()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Run() error = %v, want %q", err, tt.wantErr)
+				}
+				if got := storedData(t, clientset); len(got) != 3 {
+					t.Errorf("stored data = %q, want it unchanged", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if got := storedData(t, clientset); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stored data = %q, want %q", got, tt.want)
+			}
+			if tt.wantWarn != "" && !strings.Contains(errOut.String(), tt.wantWarn) {
+				t.Errorf("stderr = %q, want %q", errOut.String(), tt.wantWarn)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ExitCodeCancelled is the exit code when the user interrupts the command
+const ExitCodeCancelled = 130
+
+// handleSignals cancels the context on SIGINT or SIGTERM, stops the editor and
+// removes the temp file with the decoded values. While the editor is open
+// SIGINT is left to the editor, as kubectl edit and git commit do, and only
+// SIGTERM stops it. Run then returns through its deferred cleanup; a second
+// signal is no longer caught and ends the process. The returned function
+// stops listening for signals.
+func (o *EditSecretOptions) handleSignals(cancel context.CancelFunc) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		if o.watchSignals(signals, done, cancel) {
+			signal.Stop(signals)
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// watchSignals waits for a signal that cancels the command and reports
+// whether one arrived before done was closed
+func (o *EditSecretOptions) watchSignals(signals <-chan os.Signal, done <-chan struct{}, cancel context.CancelFunc) bool {
+	for {
+		select {
+		case sig := <-signals:
+			if o.interrupt(sig) {
+				cancel()
+				return true
+			}
+		case <-done:
+			return false
+		}
+	}
+}
+
+// interrupt handles sig and reports whether it cancels the command. SIGINT
+// is ignored while the editor is running.
+func (o *EditSecretOptions) interrupt(sig os.Signal) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.editorProcess != nil {
+		if sig == os.Interrupt {
+			return false
+		}
+		_ = o.editorProcess.Kill()
+	}
+	if o.tmpPath != "" {
+		os.Remove(o.tmpPath)
+	}
+	o.cancelled = true
+	return true
+}
+
+// wasCancelled reports whether a signal cancelled the command
+func (o *EditSecretOptions) wasCancelled() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.cancelled
+}
+
+// cancelledError reports that the command was interrupted, for Run to return
+// in place of the error the cancelled request or editor produced
+func (o *EditSecretOptions) cancelledError() error {
+	fmt.Fprintln(o.streams.ErrOut, "\nCancelled by user, no changes were applied.")
+	return &ExitError{Code: ExitCodeCancelled, Msg: "cancelled by user"}
+}
+
+// trackTempFile records the temp file to remove if the command is interrupted
+func (o *EditSecretOptions) trackTempFile(path string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.tmpPath = path
+}
+
+// trackEditor records the editor process to stop if the command is interrupted
+func (o *EditSecretOptions) trackEditor(p *os.Process) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.editorProcess = p
+}
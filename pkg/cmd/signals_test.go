@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startBlockingEditor starts a helper process that stays open like an editor
+// and tracks it as the running editor
+func startBlockingEditor(t *testing.T, o *EditSecretOptions) *exec.Cmd {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestEditorHelperProcess$")
+	cmd.Env = append(os.Environ(), helperBlockEnv+"=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting helper editor: %v", err)
+	}
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+	o.trackEditor(cmd.Process)
+	return cmd
+}
+
+func TestWatchSignalsIgnoresInterruptWhileEditing(t *testing.T) {
+	o, _, _, _ := newTestOptions(t)
+	editor := startBlockingEditor(t, o)
+	exited := make(chan error, 1)
+	go func() { exited <- editor.Wait() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 2)
+	done := make(chan struct{})
+	result := make(chan bool, 1)
+	go func() { result <- o.watchSignals(signals, done, cancel) }()
+
+	signals <- os.Interrupt
+	select {
+	case <-exited:
+		t.Fatal("editor stopped on SIGINT, want it left running")
+	case <-ctx.Done():
+		t.Fatal("context cancelled on SIGINT while editing")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	signals <- syscall.SIGTERM
+	if !<-result {
+		t.Fatal("watchSignals() = false, want SIGTERM to cancel the command")
+	}
+	if ctx.Err() == nil {
+		t.Error("context not cancelled after SIGTERM")
+	}
+	select {
+	case <-exited:
+	case <-time.After(10 * time.Second):
+		t.Fatal("editor still running after SIGTERM")
+	}
+	if !o.wasCancelled() {
+		t.Error("wasCancelled() = false after SIGTERM")
+	}
+}
+
+func TestWatchSignalsInterruptOutsideEditor(t *testing.T) {
+	o, _, _, _ := newTestOptions(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signals <- os.Interrupt
+	if !o.watchSignals(signals, make(chan struct{}), cancel) {
+		t.Fatal("watchSignals() = false, want SIGINT to cancel outside the editor")
+	}
+	if ctx.Err() == nil {
+		t.Error("context not cancelled after SIGINT")
+	}
+}
+
+func TestRunCancelledWhileEditing(t *testing.T) {
+	o, clientset, _, errOut := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	o.editor = "fake-editor"
+	o.editorCmd = func(name string, arg ...string) *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestEditorHelperProcess$")
+		cmd.Env = append(os.Environ(), helperBlockEnv+"=1")
+		return cmd
+	}
+	go func() {
+		for {
+			o.mu.Lock()
+			running := o.editorProcess != nil
+			o.mu.Unlock()
+			if running {
+				o.interrupt(syscall.SIGTERM)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	err := o.Run()
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeCancelled {
+		t.Fatalf("Run() error = %v, want exit code %d", err, ExitCodeCancelled)
+	}
+	if got := errOut.String(); got != "\nCancelled by user, no changes were applied.\n" {
+		t.Errorf("stderr = %q", got)
+	}
+	if got := storedData(t, clientset)["password"]; got != "old" {
+		t.Errorf("stored password = %q, want it unchanged", got)
+	}
+}
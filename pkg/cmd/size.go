@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// maxSecretSize is the most data a secret may hold; the API server
+	// rejects larger secrets
+	maxSecretSize = 1 << 20
+	// secretSizeWarning is the size above which a warning is printed
+	secretSizeWarning = 900 << 10
+)
+
+// secretDataSize returns the size the API server checks against
+// maxSecretSize: the total length of the raw (not base64-encoded) values
+func secretDataSize(data map[string][]byte) int {
+	total := 0
+	for _, v := range data {
+		total += len(v)
+	}
+	return total
+}
+
+// checkSecretSize fails if the secret is over maxSecretSize and warns if it
+// is getting close
+func (o *EditSecretOptions) checkSecretSize(secret *corev1.Secret) error {
+	size := secretDataSize(secret.Data)
+	if size > maxSecretSize {
+		return fmt.Errorf("secret %s would hold %d bytes of data, over the %d byte limit for secrets; no changes were applied", o.secretName, size, maxSecretSize)
+	}
+	if size > secretSizeWarning {
+		fmt.Fprintf(o.streams.ErrOut, "Warning: secret %s holds %d bytes of data, close to the %d byte limit for secrets\n", o.secretName, size, maxSecretSize)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSecretDataSize(t *testing.T) {
+	data := map[string][]byte{"a": make([]byte, 10), "b": make([]byte, 5), "empty": nil}
+	if got := secretDataSize(data); got != 15 {
+		t.Errorf("secretDataSize() = %d, want 15", got)
+	}
+}
+
+func TestCheckSecretSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		wantWarn bool
+		wantErr  bool
+	}{
+		{"small", 1 << 10, false, false},
+		{"at the warning", secretSizeWarning, false, false},
+		{"over the warning", secretSizeWarning + 1, true, false},
+		{"at the limit", maxSecretSize, true, false},
+		{"over the limit", maxSecretSize + 1, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, _, errOut := newTestOptions(t)
+			// Split over two keys, as the limit is on the total
+			half := tt.size / 2
+			secret := &corev1.Secret{Data: map[string][]byte{
+				"a": bytes.Repeat([]byte("x"), half),
+				"b": bytes.Repeat([]byte("y"), tt.size-half),
+			}}
+
+			err := o.checkSecretSize(secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkSecretSize() error = %v, want error %v", err, tt.wantErr)
+			}
+			if warned := strings.Contains(errOut.String(), "close to the 1048576 byte limit"); warned != tt.wantWarn {
+				t.Errorf("stderr = %q, want warning %v", errOut.String(), tt.wantWarn)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// snapshotAnnotation stores per-key HMAC-SHA256 digests of the data as it was
+// before the last edit made with --snapshot. Only digests are stored so the
+// annotation never holds a second copy of the secret values.
+const snapshotAnnotation = "kubectl-edit-secret/snapshot"
+
+// snapshot is the content of snapshotAnnotation. Each snapshot gets a random
+// key, so equal values do not give equal digests across secrets or
+// snapshots, and digests cannot be looked up in precomputed tables.
+type snapshot struct {
+	Key     string            `json:"key"`
+	Digests map[string]string `json:"digests"`
+}
+
+// hashData returns the hex-encoded HMAC-SHA256 of each value in data
+func hashData(key []byte, data map[string][]byte) map[string]string {
+	hashes := make(map[string]string, len(data))
+	for k, v := range data {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(v)
+		hashes[k] = hex.EncodeToString(mac.Sum(nil))
+	}
+	return hashes
+}
+
+// recordSnapshot stores digests of the secret's current data in its annotations
+func recordSnapshot(secret *corev1.Secret) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("failed to generate snapshot key: %w", err)
+	}
+
+	encoded, err := json.Marshal(snapshot{
+		Key:     base64.StdEncoding.EncodeToString(key),
+		Digests: hashData(key, secret.Data),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[snapshotAnnotation] = string(encoded)
+	return nil
+}
+
+// diffSnapshot compares the secret's current data against its stored snapshot
+// and returns a status line for every key present in either
+func diffSnapshot(secret *corev1.Secret) ([]string, error) {
+	raw, ok := secret.Annotations[snapshotAnnotation]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no snapshot. Edit it with --snapshot to record one", secret.Name)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal([]byte(raw), &snap); err != nil {
+		return nil, fmt.Errorf("invalid snapshot annotation %s: %w", snapshotAnnotation, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(snap.Key)
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("snapshot annotation %s has no valid key. Edit the secret with --snapshot to record a new one", snapshotAnnotation)
+	}
+
+	previous := snap.Digests
+	current := hashData(key, secret.Data)
+
+	keys := make([]string, 0, len(current)+len(previous))
+	for k := range current {
+		keys = append(keys, k)
+	}
+	for k := range previous {
+		if _, ok := current[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		oldHash, hadOld := previous[k]
+		newHash, hasNew := current[k]
+		switch {
+		case !hadOld:
+			lines = append(lines, fmt.Sprintf("%s: added", k))
+		case !hasNew:
+			lines = append(lines, fmt.Sprintf("%s: removed", k))
+		case oldHash != newHash:
+			lines = append(lines, fmt.Sprintf("%s: changed", k))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: unchanged", k))
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDiffSnapshot(t *testing.T) {
+	secret := testSecret(map[string]string{"password": "old", "token": "t", "user": "admin"})
+	if err := recordSnapshot(secret); err != nil {
+		t.Fatalf("recordSnapshot() error = %v", err)
+	}
+
+	secret.Data["password"] = []byte("new")
+	delete(secret.Data, "token")
+	secret.Data["api-key"] = []byte("k")
+
+	lines, err := diffSnapshot(secret)
+	if err != nil {
+		t.Fatalf("diffSnapshot() error = %v", err)
+	}
+	want := []string{"api-key: added", "password: changed", "token: removed", "user: unchanged"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("diffSnapshot() = %q, want %q", lines, want)
+	}
+}
+
+func TestRecordSnapshotIsKeyed(t *testing.T) {
+	first := testSecret(map[string]string{"password": "hunter2"})
+	second := testSecret(map[string]string{"password": "hunter2"})
+	for _, s := range []*corev1.Secret{first, second} {
+		if err := recordSnapshot(s); err != nil {
+			t.Fatalf("recordSnapshot() error = %v", err)
+		}
+	}
+
+	sum := sha256.Sum256([]byte("hunter2"))
+	for _, s := range []*corev1.Secret{first, second} {
+		if strings.Contains(s.Annotations[snapshotAnnotation], hex.EncodeToString(sum[:])) {
+			t.Errorf("snapshot %s holds the plain SHA-256 of the value", s.Annotations[snapshotAnnotation])
+		}
+	}
+	if first.Annotations[snapshotAnnotation] == second.Annotations[snapshotAnnotation] {
+		t.Error("equal values in two secrets gave the same snapshot")
+	}
+}
+
+func TestDiffSnapshotErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		wantErr    string
+	}{
+		{"no snapshot", "", "has no snapshot"},
+		{"unkeyed snapshot", `{"password":"0b14d501a594442a01c6859541bcb3e8164d183d32937b851835442f69d5c94e"}`, "has no valid key"},
+		{"invalid JSON", "{", "invalid snapshot annotation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := testSecret(map[string]string{"password": "old"})
+			if tt.annotation != "" {
+				secret.Annotations = map[string]string{snapshotAnnotation: tt.annotation}
+			}
+			_, err := diffSnapshot(secret)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("diffSnapshot() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunSnapshotThenDiffPrevious(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old", "user": "admin"}))
+	o.snapshot = true
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	stored, err := clientset.Tracker().Get(corev1.SchemeGroupVersion.WithResource("secrets"), "default", "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, _, out, _ := newTestOptions(t, stored)
+	o.diffPrevious = true
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := "password: changed\nuser: unchanged\n"; out.String() != want {
+		t.Errorf("stdout = %q, want %q", out.String(), want)
+	}
+}
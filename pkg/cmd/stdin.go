@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// readStdinSecret decodes a Secret manifest (YAML or JSON) from the input
+// stream and reconciles its name and namespace with the arguments and flags
+func (o *EditSecretOptions) readStdinSecret(explicitNamespace bool) error {
+	raw, err := io.ReadAll(o.streams.In)
+	if err != nil {
+		return fmt.Errorf("failed to read secret from stdin: %w", err)
+	}
+
+	obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(raw, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decode secret from stdin: %w", err)
+	}
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return fmt.Errorf("stdin contains a %s, expected a Secret", gvk.Kind)
+	}
+
+	switch {
+	case o.secretName == "":
+		o.secretName = secret.Name
+	case secret.Name != "" && secret.Name != o.secretName:
+		return fmt.Errorf("secret name %q from stdin does not match SECRET_NAME %q", secret.Name, o.secretName)
+	}
+
+	if secret.Namespace != "" {
+		if explicitNamespace && secret.Namespace != o.namespace {
+			return fmt.Errorf("namespace %q from stdin does not match --namespace %q", secret.Namespace, o.namespace)
+		}
+		o.namespace = secret.Namespace
+	}
+
+	secret.Name = o.secretName
+	secret.Namespace = o.namespace
+	o.stdinSecret = secret
+	return nil
+}
+
+// reconcileStdinSecret checks the piped-in secret against the live one. The
+// live resourceVersion is used when the manifest has none, so the update is
+// still guarded against concurrent changes, and a missing secret is created.
+func (o *EditSecretOptions) reconcileStdinSecret(ctx context.Context) error {
+	live, err := o.clientset.CoreV1().Secrets(o.namespace).Get(ctx, o.secretName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		o.stdinSecret.ResourceVersion = ""
+		o.creating = true
+		fmt.Fprintf(o.streams.ErrOut, "Secret %s does not exist in namespace %s, it will be created.\n", o.secretName, o.namespace)
+	case err != nil:
+		return fmt.Errorf("failed to get secret %s: %w", o.secretName, o.forbiddenError("get", err))
+	case o.stdinSecret.ResourceVersion == "":
+		o.stdinSecret.ResourceVersion = live.ResourceVersion
+	}
+	return nil
+}
+
+// promptStdin returns the input for prompts. Like the editor, they read from
+// the controlling terminal when the secret was piped in on stdin.
+func (o *EditSecretOptions) promptStdin() io.Reader {
+	if o.stdinSecret == nil {
+		return o.streams.In
+	}
+
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return o.streams.In
+	}
+	return tty
+}
+
+// editorStdin returns the input for the editor. When the secret was piped in
+// on stdin, the editor reads from the controlling terminal instead.
+func (o *EditSecretOptions) editorStdin() (*os.File, func()) {
+	if o.stdinSecret == nil {
+		return os.Stdin, func() {}
+	}
+
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return os.Stdin, func() {}
+	}
+	return tty, func() { tty.Close() }
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const stdinManifest = `apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+  namespace: default
+data:
+  password: b2xk
+`
+
+func TestRunStdinUsesLiveResourceVersion(t *testing.T) {
+	o, clientset, _, _ := newTestOptions(t, testSecret(map[string]string{"password": "old"}))
+	o.streams.In = strings.NewReader(stdinManifest)
+	if err := o.readStdinSecret(false); err != nil {
+		t.Fatalf("readStdinSecret() error = %v", err)
+	}
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var updated *corev1.Secret
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "update" {
+			updated = action.(k8stesting.UpdateAction).GetObject().(*corev1.Secret)
+		}
+	}
+	if updated == nil {
+		t.Fatal("no update action")
+	}
+	if updated.ResourceVersion != "1" {
+		t.Errorf("update resourceVersion = %q, want the live %q", updated.ResourceVersion, "1")
+	}
+	if got := storedData(t, clientset)["password"]; got != "new" {
+		t.Errorf("stored password = %q, want %q", got, "new")
+	}
+}
+
+func TestRunStdinCreatesMissingSecret(t *testing.T) {
+	o, clientset, out, _ := newTestOptions(t)
+	o.streams.In = strings.NewReader(stdinManifest)
+	if err := o.readStdinSecret(false); err != nil {
+		t.Fatalf("readStdinSecret() error = %v", err)
+	}
+	fakeEditor(o, 0, replaceValue("password: old", "password: new"))
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	actions := clientset.Actions()
+	if last := actions[len(actions)-1]; !last.Matches("create", "secrets") {
+		t.Errorf("last action = %s %s, want a create", last.GetVerb(), last.GetResource().Resource)
+	}
+	if got := out.String(); got != "secret/db created\n" {
+		t.Errorf("stdout = %q, want the created line", got)
+	}
+	if got := storedData(t, clientset)["password"]; got != "new" {
+		t.Errorf("stored password = %q, want %q", got, "new")
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// suggestDistance is the most edits a name may be away from the requested one
+// to be suggested
+const suggestDistance = 2
+
+// maxSuggestions limits how many names are suggested
+const maxSuggestions = 3
+
+// suggestSecretNames lists the secrets in the namespace and returns a
+// "Did you mean" hint for names close to the requested one, or an empty
+// string if there are none or they cannot be listed
+func (o *EditSecretOptions) suggestSecretNames(ctx context.Context) string {
+	secrets, err := o.clientset.CoreV1().Secrets(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ""
+	}
+
+	names := make([]string, 0, len(secrets.Items))
+	for _, s := range secrets.Items {
+		names = append(names, s.Name)
+	}
+
+	matches := closestNames(o.secretName, names)
+	if len(matches) == 0 {
+		return ""
+	}
+	return "Did you mean: " + strings.Join(matches, ", ") + "?"
+}
+
+// closestNames returns the candidates within suggestDistance edits of name,
+// closest first and then alphabetically, limited to maxSuggestions
+func closestNames(name string, candidates []string) []string {
+	distances := make(map[string]int)
+	for _, c := range candidates {
+		if d := levenshtein(name, c); d <= suggestDistance {
+			distances[c] = d
+		}
+	}
+
+	matches := make([]string, 0, len(distances))
+	for c := range distances {
+		matches = append(matches, c)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if distances[matches[i]] != distances[matches[j]] {
+			return distances[matches[i]] < distances[matches[j]]
+		}
+		return matches[i] < matches[j]
+	})
+
+	if len(matches) > maxSuggestions {
+		matches = matches[:maxSuggestions]
+	}
+	return matches
+}
+
+// levenshtein returns the number of single-character insertions, deletions,
+// and substitutions needed to turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"db", "db", 0},
+		{"", "abc", 3},
+		{"db-creds", "db-cred", 1},
+		{"db-creds", "db-credz", 1},
+		{"kitten", "sitting", 3},
+		{"naïve", "naive", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := levenshtein(tt.b, tt.a); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestClosestNames(t *testing.T) {
+	candidates := []string{"db-creds", "db-cred", "db-credz", "dB-creds", "db-creds-old", "api-token", "db-crds"}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		// Closest first, then alphabetically, capped at maxSuggestions
+		{"db-creds", []string{"db-creds", "dB-creds", "db-crds"}},
+		{"db-credss", []string{"db-creds", "dB-creds", "db-crds"}},
+		{"db-creds-ol", []string{"db-creds-old"}},
+		{"api-tokens", []string{"api-token"}},
+		{"redis", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := closestNames(tt.name, candidates)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("closestNames(%q) = %s, want %s", tt.name, strings.Join(got, ","), strings.Join(tt.want, ","))
+			}
+		})
+	}
+}
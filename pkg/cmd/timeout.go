@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultTimeout bounds each group of API requests unless --timeout is set
+const defaultTimeout = 30 * time.Second
+
+// withTimeout bounds ctx by --timeout. A zero timeout means no limit. Time
+// spent in the editor or at a prompt must not run under this context.
+func (o *EditSecretOptions) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, o.timeout)
+}
+
+// timeoutError explains an error caused by --timeout running out
+func (o *EditSecretOptions) timeoutError(err error) error {
+	if err != nil && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s waiting for the API server (see --timeout): %w", o.timeout, err)
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// hangingAPIServer returns options whose client talks to an API server that
+// never answers before the request is cancelled
+func hangingAPIServer(t *testing.T) *EditSecretOptions {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	o, _, _, _ := newTestOptions(t)
+	o.configFlags = testConfigFlags(srv.URL)
+	clientset, err := newClientset(o.configFlags, "", o.streams)
+	if err != nil {
+		t.Fatalf("newClientset() error = %v", err)
+	}
+	o.clientset = clientset
+	return o
+}
+
+func TestGetWithExpiredContext(t *testing.T) {
+	o := hangingAPIServer(t)
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+
+	_, err := o.clientset.CoreV1().Secrets("default").Get(ctx, "db", metav1.GetOptions{})
+	err = o.timeoutError(err)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want it to wrap context.DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "timed out after") {
+		t.Errorf("error = %q, want it to point at --timeout", err)
+	}
+}
+
+func TestRunTimesOut(t *testing.T) {
+	o := hangingAPIServer(t)
+	o.timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err := o.timeoutError(o.Run())
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Fatalf("Run() error = %v, want a --timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() took %s, want it cut short by --timeout", elapsed)
+	}
+}
+
+func TestWithTimeoutZeroHasNoDeadline(t *testing.T) {
+	o := &EditSecretOptions{}
+	ctx, cancel := o.withTimeout(context.Background())
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("--timeout=0 set a deadline")
+	}
+}
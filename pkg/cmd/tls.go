@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// checkTLS checks that tls.crt holds PEM certificates and tls.key a PEM
+// private key. Keys that are not in the edited values are not checked.
+func checkTLS(edited map[string]string) error {
+	if crt, ok := edited[corev1.TLSCertKey]; ok {
+		if err := parseCertificates([]byte(crt)); err != nil {
+			return fmt.Errorf("invalid %s: %w", corev1.TLSCertKey, err)
+		}
+	}
+	if key, ok := edited[corev1.TLSPrivateKeyKey]; ok {
+		if err := parsePrivateKey([]byte(key)); err != nil {
+			return fmt.Errorf("invalid %s: %w", corev1.TLSPrivateKeyKey, err)
+		}
+	}
+	return nil
+}
+
+// parseCertificates checks that data holds one or more PEM certificates
+func parseCertificates(data []byte) error {
+	found := false
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return err
+		}
+		found = true
+	}
+	if !found {
+		return errors.New("no PEM certificate found")
+	}
+	return nil
+}
+
+// parsePrivateKey checks that data holds a PKCS#1, PKCS#8, or EC private key
+// in PEM form
+func parsePrivateKey(data []byte) error {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return errors.New("no PEM private key found")
+		}
+		if block.Type != "PRIVATE KEY" && block.Type != "RSA PRIVATE KEY" && block.Type != "EC PRIVATE KEY" {
+			continue
+		}
+
+		if _, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+			return nil
+		}
+		if _, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+			return nil
+		}
+		if _, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+			return nil
+		}
+		return fmt.Errorf("failed to parse %s block", block.Type)
+	}
+}
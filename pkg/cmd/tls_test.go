@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// generateTLS returns a self-signed PEM certificate and its PKCS#8 key
+func generateTLS(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "db.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	crt := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return string(crt), string(keyPEM)
+}
+
+// corruptPEM flips a character in the middle of the base64 body
+func corruptPEM(s string) string {
+	lines := strings.Split(s, "\n")
+	mid := lines[len(lines)/2]
+	lines[len(lines)/2] = "!!" + mid[2:]
+	return strings.Join(lines, "\n")
+}
+
+func TestCheckTLS(t *testing.T) {
+	crt, key := generateTLS(t)
+
+	tests := []struct {
+		name    string
+		edited  map[string]string
+		wantErr string
+	}{
+		{"valid", map[string]string{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}, ""},
+		{"only the key edited", map[string]string{corev1.TLSPrivateKeyKey: key}, ""},
+		{"corrupted certificate", map[string]string{corev1.TLSCertKey: corruptPEM(crt), corev1.TLSPrivateKeyKey: key}, "invalid tls.crt"},
+		{"corrupted key", map[string]string{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: corruptPEM(key)}, "invalid tls.key"},
+		{"key in the certificate", map[string]string{corev1.TLSCertKey: key}, "invalid tls.crt: no PEM certificate found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTLS(tt.edited)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("checkTLS() error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("checkTLS() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
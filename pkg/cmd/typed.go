@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// keyNameRE matches the secret key names the API server accepts
+var keyNameRE = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// maxKeyNameLength is the longest key name the API server accepts
+const maxKeyNameLength = 253
+
+// editCheck returns the validation for the edited values: key names are
+// always checked, and well-known secret types add their own checks
+func editCheck(secret *corev1.Secret) func(map[string]string) error {
+	var typed func(map[string]string) error
+	switch secret.Type {
+	case corev1.SecretTypeTLS:
+		typed = checkTLS
+	case corev1.SecretTypeDockerConfigJson:
+		typed = checkDockerConfig
+	}
+
+	return func(edited map[string]string) error {
+		if err := checkKeyNames(edited); err != nil {
+			return err
+		}
+		if typed != nil {
+			return typed(edited)
+		}
+		return nil
+	}
+}
+
+// checkKeyNames reports every key the API server would reject, so the user
+// can fix them before anything is sent
+func checkKeyNames(edited map[string]string) error {
+	var problems []string
+	for _, k := range sortedStringKeys(edited) {
+		if reason := keyNameProblem(k); reason != "" {
+			problems = append(problems, fmt.Sprintf("  %q %s", k, reason))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid key names, keys must match %s:\n%s", keyNameRE, strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// keyNameProblem describes why the API server would reject key, or returns
+// an empty string if it is valid
+func keyNameProblem(key string) string {
+	switch {
+	case key == "":
+		return "must not be empty"
+	case key == "." || key == "..":
+		return "must not be '.' or '..'"
+	case len(key) > maxKeyNameLength:
+		return fmt.Sprintf("must be at most %d characters", maxKeyNameLength)
+	case !keyNameRE.MatchString(key):
+		return "may only contain letters, digits, '-', '_', and '.'"
+	}
+	return ""
+}
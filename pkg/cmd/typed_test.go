@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckKeyNames(t *testing.T) {
+	tests := []struct {
+		key     string
+		problem string
+	}{
+		{"password", ""},
+		{"tls.crt", ""},
+		{"DB_PASS-2", ""},
+		{".dockerconfigjson", ""},
+		{"my key", "may only contain letters, digits, '-', '_', and '.'"},
+		{"path/to/key", "may only contain letters, digits, '-', '_', and '.'"},
+		{"clé", "may only contain letters, digits, '-', '_', and '.'"},
+		{"密码", "may only contain letters, digits, '-', '_', and '.'"},
+		{"..", "must not be '.' or '..'"},
+		{"", "must not be empty"},
+		{strings.Repeat("k", maxKeyNameLength+1), "must be at most 253 characters"},
+	}
+
+	for _, tt := range tests {
+		err := checkKeyNames(map[string]string{tt.key: "value", "valid": "value"})
+		if tt.problem == "" {
+			if err != nil {
+				t.Errorf("checkKeyNames(%q) error = %v, want nil", tt.key, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.problem) {
+			t.Errorf("checkKeyNames(%q) error = %v, want %q", tt.key, err, tt.problem)
+		}
+	}
+}
+
+func TestCheckKeyNamesListsEveryProblem(t *testing.T) {
+	err := checkKeyNames(map[string]string{"a b": "", "c/d": "", "ok": ""})
+	if err == nil {
+		t.Fatal("checkKeyNames() error = nil, want an error")
+	}
+	want := "\n  \"a b\" may only contain letters, digits, '-', '_', and '.'\n  \"c/d\" may only contain letters, digits, '-', '_', and '.'"
+	if !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("checkKeyNames() error = %q, want it to end with %q", err, want)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+const (
+	// latestReleaseURL is the GitHub API endpoint for the newest release
+	latestReleaseURL = "https://api.github.com/repos/BardiaYaghmaie/kubectl-edit-secret/releases/latest"
+	// updateCheckTimeout bounds how long the update check may delay the command
+	updateCheckTimeout = 3 * time.Second
+	// noUpdateCheckEnv disables the update check when set to any non-empty value
+	noUpdateCheckEnv = "KUBECTL_EDIT_SECRET_NO_UPDATE_CHECK"
+)
+
+// updateCheckEnabled reports whether the update check should run
+func (o *EditSecretOptions) updateCheckEnabled() bool {
+	return o.checkUpdate && !o.noUpdateCheck && os.Getenv(noUpdateCheckEnv) == ""
+}
+
+// checkForUpdate prints a notice if a newer release is available.
+// Any failure is ignored so the check never gets in the way.
+func (o *EditSecretOptions) checkForUpdate() {
+	current, err := version.ParseGeneric(Version)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", fmt.Sprintf("kubectl-edit-secret/%s", Version))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return
+	}
+
+	latest, err := version.ParseGeneric(release.TagName)
+	if err != nil || !current.LessThan(latest) {
+		return
+	}
+
+	fmt.Fprintf(o.streams.ErrOut, "A newer version of kubectl-edit-secret is available: %s (current %s). Upgrade with: kubectl krew upgrade edit-secret\n", release.TagName, Version)
+}